@@ -12,6 +12,34 @@ const (
 	INCOME
 	EXPENSE
 	CREATE_TRANSFER
+	SET_GOAL
+	SET_LIMIT
+	GET_LIMITS
+	SET_OVERDRAFT
+	CREATE_SCHEDULE
+	GET_SCHEDULES
+	PAUSE_SCHEDULE
+	EDIT_SCHEDULE
+	DESTROY_SCHEDULE
+	SET_DIGEST
+	GET_HISTORY
+	UNDO
+	EDIT_OPERATION
+	GET_REPORT
+	SEARCH
+	RENAME_BANK
+	MERGE_BANKS
+	MOVE_BANK
+	IMPORT_STATEMENT
+	EXPORT
+	SET_LEDGER_ACCOUNTS
+	CHART
+	SHARE_BANK
+	CREATE_DEBT
+	REPAY_DEBT
+	GET_DEBTS
+	SPLIT
+	SETTLE
 )
 
 var BotCommands = map[BotCommand]string{
@@ -24,4 +52,36 @@ var BotCommands = map[BotCommand]string{
 	INCOME:              "/income",
 	EXPENSE:             "/expense",
 	CREATE_TRANSFER:     "/create_transfer",
+	SET_GOAL:            "/set_goal",
+	SET_LIMIT:           "/set_limit",
+	GET_LIMITS:          "/get_limits",
+	SET_OVERDRAFT:       "/set_overdraft",
+	CREATE_SCHEDULE:     "/create_schedule",
+	GET_SCHEDULES:       "/schedules",
+	PAUSE_SCHEDULE:      "/pause_schedule",
+	EDIT_SCHEDULE:       "/edit_schedule",
+	DESTROY_SCHEDULE:    "/destroy_schedule",
+	SET_DIGEST:          "/digest",
+	GET_HISTORY:         "/history",
+	UNDO:                "/undo",
+	GET_REPORT:          "/report",
+	SEARCH:              "/search",
+	RENAME_BANK:         "/rename_bank",
+	MERGE_BANKS:         "/merge_banks",
+	MOVE_BANK:           "/move_bank",
+	EXPORT:              "/export",
+	SET_LEDGER_ACCOUNTS: "/ledger_accounts",
+	CHART:               "/chart",
+	SHARE_BANK:          "/share_bank",
+	CREATE_DEBT:         "/create_debt",
+	REPAY_DEBT:          "/repay_debt",
+	GET_DEBTS:           "/debts",
+	SPLIT:               "/split",
+	SETTLE:              "/settle",
+}
+
+var OperationNames = map[BotCommand]string{
+	INCOME:          "Доход",
+	EXPENSE:         "Расход",
+	CREATE_TRANSFER: "Перевод",
 }
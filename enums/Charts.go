@@ -0,0 +1,17 @@
+package enums
+
+type Chart int
+
+const (
+	UndefinedChart Chart = iota
+	BALANCE_CHART
+	EXPENSES_CHART
+	MONTHLY_CHART
+)
+
+var ChartNames = map[Chart]string{
+	UndefinedChart: "",
+	BALANCE_CHART:  "Баланс копилок",
+	EXPENSES_CHART: "Расходы по копилкам",
+	MONTHLY_CHART:  "Доходы и расходы по месяцам",
+}
@@ -0,0 +1,21 @@
+package enums
+
+type DebtDirection int
+
+const (
+	UndefinedDebtDirection DebtDirection = iota
+	LENT
+	BORROWED
+)
+
+var DebtDirections = map[DebtDirection]string{
+	UndefinedDebtDirection: "",
+	LENT:                   "lent",
+	BORROWED:               "borrowed",
+}
+
+var DebtDirectionNames = map[DebtDirection]string{
+	UndefinedDebtDirection: "",
+	LENT:                   "Мне должны",
+	BORROWED:               "Я должен",
+}
@@ -0,0 +1,27 @@
+package enums
+
+type OperationField int
+
+const (
+	UndefinedOperationField OperationField = iota
+	AMOUNT
+	COMMENT
+	BANK
+	DATE
+)
+
+var OperationFields = map[OperationField]string{
+	UndefinedOperationField: "",
+	AMOUNT:                  "amount",
+	COMMENT:                 "comment",
+	BANK:                    "bank",
+	DATE:                    "date",
+}
+
+var OperationFieldNames = map[OperationField]string{
+	UndefinedOperationField: "",
+	AMOUNT:                  "Сумма",
+	COMMENT:                 "Комментарий",
+	BANK:                    "Копилка",
+	DATE:                    "Дата",
+}
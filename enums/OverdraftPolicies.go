@@ -0,0 +1,24 @@
+package enums
+
+type OverdraftPolicy int
+
+const (
+	UndefinedOverdraftPolicy OverdraftPolicy = iota
+	FORBID
+	WARN
+	ALLOW
+)
+
+var OverdraftPolicies = map[OverdraftPolicy]string{
+	UndefinedOverdraftPolicy: "",
+	FORBID:                   "forbid",
+	WARN:                     "warn",
+	ALLOW:                    "allow",
+}
+
+var OverdraftPolicyNames = map[OverdraftPolicy]string{
+	UndefinedOverdraftPolicy: "",
+	FORBID:                   "Запретить",
+	WARN:                     "Предупреждать",
+	ALLOW:                    "Разрешить",
+}
@@ -0,0 +1,42 @@
+package enums
+
+type Period int
+
+const (
+	UndefinedPeriod Period = iota
+	DAY
+	WEEK
+	MONTH
+)
+
+var Periods = map[Period]string{
+	UndefinedPeriod: "",
+	DAY:             "day",
+	WEEK:            "week",
+	MONTH:           "month",
+}
+
+var PeriodNames = map[Period]string{
+	UndefinedPeriod: "",
+	DAY:             "День",
+	WEEK:            "Неделя",
+	MONTH:           "Месяц",
+}
+
+type PeriodFilter int
+
+const (
+	UndefinedPeriodFilter PeriodFilter = iota
+	ALL_TIME
+	THIS_WEEK
+	THIS_MONTH
+	LAST_MONTH
+)
+
+var PeriodFilters = map[PeriodFilter]string{
+	UndefinedPeriodFilter: "",
+	ALL_TIME:              "За всё время",
+	THIS_WEEK:             "Эта неделя",
+	THIS_MONTH:            "Этот месяц",
+	LAST_MONTH:            "Прошлый месяц",
+}
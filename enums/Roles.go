@@ -0,0 +1,30 @@
+package enums
+
+type Role int
+
+const (
+	UndefinedRole Role = iota
+	VIEWER
+	REQUESTER
+	CONTRIBUTOR
+	ADMIN
+	OWNER
+)
+
+var Roles = map[Role]string{
+	UndefinedRole: "",
+	VIEWER:        "viewer",
+	REQUESTER:     "requester",
+	CONTRIBUTOR:   "contributor",
+	ADMIN:         "admin",
+	OWNER:         "owner",
+}
+
+var RoleNames = map[Role]string{
+	UndefinedRole: "",
+	VIEWER:        "Просмотр",
+	REQUESTER:     "Запросы на одобрение",
+	CONTRIBUTOR:   "Внесение операций",
+	ADMIN:         "Администратор",
+	OWNER:         "Владелец",
+}
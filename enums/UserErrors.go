@@ -6,17 +6,95 @@ type UserError int
 
 const (
 	NO_BANKS UserError = iota
+	NO_LIMITS
+	NO_SCHEDULES
+	NOTHING_TO_UNDO
 	BANK_NAME_IS_EXIST
 	BANK_NOT_FOUND
 	INCORRECT_VALUE
+	INCORRECT_DATE
+	INSUFFICIENT_FUNDS
+	INCORRECT_SCHEDULE
+	SCHEDULE_NOT_FOUND
+	INCORRECT_TIMEZONE
+	INCORRECT_PERIOD
+	OPERATION_NOT_FOUND
+	UNDO_EXPIRED
+	ALREADY_REVERTED
+	OPERATION_IS_REVERTED
+	OPERATION_IS_PENDING
+	SAME_BANK
+	INCORRECT_PARENT
+	NON_POSITIVE_AMOUNT
+	AMOUNT_TOO_LARGE
+	INCORRECT_RECEIPT
+	RECEIPT_IS_IMPORTED
+	UNSUPPORTED_FILE
+	INCORRECT_STATEMENT
+	INCORRECT_COLUMNS
+	STATEMENT_IS_IMPORTED
+	INCORRECT_LEDGER_ACCOUNT
+	NO_CHART_DATA
+	NO_PERMISSION
+	INVITE_NOT_FOUND
+	OWN_BANK
+	REQUEST_NOT_FOUND
+	CANNOT_APPROVE
+	NOT_APPROVER
+	NO_DEBTS
+	DEBT_NOT_FOUND
+	DEBT_OVERPAID
+	GROUP_ONLY
+	NO_SPLITS
+	SETTLEMENT_NOT_FOUND
+	AMBIGUOUS_PARTICIPANT
 	UNEXPECTED_ERROR
 )
 
 var developer = os.Getenv("DEVELOPER")
 var UserErrors = map[UserError]string{
-	NO_BANKS:           "На твоем аккаунте нет ни одной копилки!",
-	BANK_NAME_IS_EXIST: "Копилка с таким названием уже существует. Попробуй снова",
-	BANK_NOT_FOUND:     "Копилка с таким названием не найдена. Попробуй снова",
-	INCORRECT_VALUE:    "Некорректное значение. Попробуй снова",
-	UNEXPECTED_ERROR:   "Произошла непредвиденная ошибка. Пожалуйста, напиши об этом разработчику @" + developer,
+	NO_BANKS:                 "На твоем аккаунте нет ни одной копилки!",
+	NO_LIMITS:                "Ни у одной копилки не установлен лимит. Установить его можно командой /set_limit",
+	NO_SCHEDULES:             "У тебя нет ни одной регулярной операции. Создать её можно командой /create_schedule",
+	NOTHING_TO_UNDO:          "Нет операций, которые можно отменить",
+	BANK_NAME_IS_EXIST:       "Копилка с таким названием уже существует. Попробуй снова",
+	BANK_NOT_FOUND:           "Копилка с таким названием не найдена. Попробуй снова",
+	INCORRECT_VALUE:          "Некорректное значение. Попробуй снова",
+	INCORRECT_DATE:           "Некорректная дата. Используй формат ДД.ММ.ГГГГ и попробуй снова",
+	INSUFFICIENT_FUNDS:       "В копилке недостаточно средств, а её баланс не может уйти в минус. Введи другую сумму",
+	INCORRECT_SCHEDULE:       "Не получилось разобрать расписание. Напиши, например, «каждый месяц 5 числа в 10:00», «каждую пятницу» или cron-выражение «0 9 5 * *»",
+	SCHEDULE_NOT_FOUND:       "Регулярная операция не найдена. Попробуй снова",
+	INCORRECT_TIMEZONE:       "Не получилось распознать часовой пояс. Напиши, например, Europe/Moscow или %2B3",
+	INCORRECT_PERIOD:         "Не получилось распознать период. Выбери его на клавиатуре или напиши в формате ДД.ММ.ГГГГ-ДД.ММ.ГГГГ",
+	OPERATION_NOT_FOUND:      "Операция не найдена",
+	UNDO_EXPIRED:             "Время, в течение которого операцию можно отменить, истекло",
+	ALREADY_REVERTED:         "Эта операция уже отменена",
+	OPERATION_IS_REVERTED:    "Отменённую операцию и её отмену нельзя изменить",
+	OPERATION_IS_PENDING:     "Операция ожидает одобрения, её пока нельзя изменить или удалить",
+	SAME_BANK:                "Нужно выбрать другую копилку. Попробуй снова",
+	INCORRECT_PARENT:         "Копилку нельзя вложить в саму себя или в одну из её вложенных копилок. Попробуй снова",
+	NON_POSITIVE_AMOUNT:      "Сумма должна быть больше нуля. Попробуй снова",
+	AMOUNT_TOO_LARGE:         "Слишком большая сумма. Попробуй снова",
+	INCORRECT_RECEIPT:        "Не получилось распознать чек. Пришли фото QR-кода крупнее или скопируй строку, которая в нём записана",
+	RECEIPT_IS_IMPORTED:      "Этот чек уже был добавлен",
+	UNSUPPORTED_FILE:         "Пока я умею импортировать только банковские выписки в формате CSV",
+	INCORRECT_STATEMENT:      "В выписке не нашлось ни одной операции с датой и суммой",
+	INCORRECT_COLUMNS:        "Некорректные номера колонок. Напиши три номера через пробел, например «1 3 5»",
+	STATEMENT_IS_IMPORTED:    "Все операции из этой выписки уже добавлены",
+	INCORRECT_LEDGER_ACCOUNT: "Некорректный счёт. Напиши его через двоеточие, например Expenses:Прочее",
+	NO_CHART_DATA:            "За этот период нет операций, по которым можно построить график",
+	NO_PERMISSION:            "У тебя недостаточно прав для этого действия с копилкой. Выбери другую",
+	INVITE_NOT_FOUND:         "Приглашение не найдено или уже использовано",
+	OWN_BANK:                 "Это твоя собственная копилка",
+	REQUEST_NOT_FOUND:        "Запрос не найден или уже рассмотрен",
+	CANNOT_APPROVE:           "В копилке недостаточно средств, а её баланс не может уйти в минус. Запрос пока нельзя одобрить",
+	NOT_APPROVER:             "Одобрять и отклонять запросы могут только владелец и администраторы копилки",
+	NO_DEBTS:                 "У тебя нет открытых долгов. Записать долг можно командой /create_debt",
+	DEBT_NOT_FOUND:           "Долг не найден. Попробуй снова",
+	DEBT_OVERPAID:            "Сумма больше оставшейся части долга. Введи другую сумму",
+	GROUP_ONLY:               "Эта команда работает только в групповом чате",
+	NO_SPLITS:                "В этом чате ещё нет общих расходов. Записать расход можно командой /split",
+	SETTLEMENT_NOT_FOUND:     "Эти переводы уже отмечены. Актуальные балансы покажет команда /settle",
+	AMBIGUOUS_PARTICIPANT:    "Это имя носят несколько участников. Выбери нужного на клавиатуре",
+	UNEXPECTED_ERROR:         "Произошла непредвиденная ошибка. Пожалуйста, напиши об этом разработчику @" + developer,
 }
@@ -2,7 +2,13 @@ module BIEAS_bot
 
 go 1.17
 
-require github.com/matoous/go-nanoid/v2 v2.0.0
+require (
+	github.com/makiuchi-d/gozxing v0.1.1
+	github.com/matoous/go-nanoid/v2 v2.0.0
+	golang.org/x/image v0.5.0
+)
+
+require golang.org/x/xerrors v0.0.0-20200804184101-5ec99f83aff1 // indirect
 
 require (
 	github.com/go-stack/stack v1.8.0 // indirect
@@ -15,7 +21,7 @@ require (
 	github.com/xdg-go/stringprep v1.0.2 // indirect
 	github.com/youmark/pkcs8 v0.0.0-20181117223130-1be2e3e5546d // indirect
 	go.mongodb.org/mongo-driver v1.8.1
-	golang.org/x/crypto v0.0.0-20201216223049-8b5274cf687f // indirect
-	golang.org/x/sync v0.0.0-20190911185100-cd5d95a43a6e // indirect
-	golang.org/x/text v0.3.5 // indirect
+	golang.org/x/crypto v0.0.0-20210921155107-089bfa567519 // indirect
+	golang.org/x/sync v0.0.0-20220722155255-886fb9371eb4 // indirect
+	golang.org/x/text v0.7.0
 )
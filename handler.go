@@ -4,16 +4,374 @@ import (
 	"BIEAS_bot/enums"
 	"BIEAS_bot/models"
 	"BIEAS_bot/utils"
+	"errors"
 	"log"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 )
 
 func handler(update models.Update) {
-	if update.Message.Text == enums.BotCommands[enums.START] {
+	if update.Message.Chat.Type == "group" || update.Message.Chat.Type == "supergroup" {
+		update.Message.Text = utils.TrimBotMention(update.Message.Text)
+
+		// a message is addressed to the bot when its text or caption starts with a mention of the bot
+		// or when it replies to a message of the bot
+		addressed := bot.Username != "" &&
+			update.Message.ReplyToMessage != nil &&
+			update.Message.ReplyToMessage.From.Username == bot.Username
+
+		if mention := "@" + bot.Username; bot.Username != "" && strings.HasPrefix(update.Message.Text, mention) {
+			update.Message.Text = strings.TrimSpace(strings.TrimPrefix(update.Message.Text, mention))
+			addressed = true
+		} else if bot.Username != "" && strings.HasPrefix(update.Message.Caption, mention) {
+			addressed = true
+		}
+
+		// the bot reads the whole group conversation, so it answers only to messages addressed to it,
+		// and photos and documents shared with the group never interrupt a dialog
+		if (len(update.Message.Photo) > 0 || update.Message.Document.FileId != "") && !addressed {
+			return
+		}
+
+		if !strings.HasPrefix(update.Message.Text, "/") &&
+			!addressed &&
+			!utils.IsReceipt(update.Message.Text) &&
+			len(update.Message.Photo) < 1 &&
+			update.Message.Document.FileId == "" &&
+			!processing.Exists(update.Message.Chat.ChatId, update.Message.From.UserId) {
+			return
+		}
+
+		// replies are sent to the member's message, so the keyboard is shown only to that member
+		bot.ReplyTo = update.Message.MessagId
+		defer func() { bot.ReplyTo = 0 }()
+	}
+
+	if update.CallbackQuery.CallbackQueryId != "" {
+		// ---------------------------------------------------------------------------------- handle callback query
+		chat := update.CallbackQuery.Message.Chat.ChatId
+
+		if strings.HasPrefix(update.CallbackQuery.Data, "history:") {
+			filter, page, err := utils.DecodeFilter(update.CallbackQuery.Data)
+			if err != nil {
+				log.Println(err)
+			} else if history, buttons, err := utils.GetHistory(ctx, &db, chat, filter, page); err != nil {
+				log.Println(err)
+			} else {
+				for _, row := range buttons {
+					bot.InlineKeyboard.Create(row)
+				}
+
+				if err = bot.EditMessage(chat, update.CallbackQuery.Message.MessagId, history); err != nil {
+					log.Println(err)
+				}
+
+				bot.InlineKeyboard.Destroy()
+			}
+		} else if strings.HasPrefix(update.CallbackQuery.Data, "undo:") {
+			since := time.Now().Add(-undoWindow)
+
+			text := "Операция отменена!%0A"
+
+			if operation, err := utils.GetOperation(
+				ctx,
+				&db,
+				chat,
+				strings.TrimPrefix(update.CallbackQuery.Data, "undo:"),
+			); err != nil {
+				log.Println(err)
+
+				text = enums.UserErrors[enums.UNEXPECTED_ERROR]
+				if utils.IsUserError(err) {
+					text = err.Error()
+				}
+			} else if err = checkOperationRole(operation, chat, update.CallbackQuery.From.UserId, enums.CONTRIBUTOR); err != nil {
+				log.Println(err)
+
+				text = enums.UserErrors[enums.UNEXPECTED_ERROR]
+				if utils.IsUserError(err) {
+					text = err.Error()
+				}
+			} else if banks, err := utils.UndoOperation(ctx, &db, operation, since); err != nil {
+				log.Println(err)
+
+				text = enums.UserErrors[enums.UNEXPECTED_ERROR]
+				if utils.IsUserError(err) {
+					text = err.Error()
+				}
+			} else {
+				for _, bank := range banks {
+					text += "%0AБаланс копилки " + bank.Name + " составляет " + strconv.Itoa(bank.Balance) + " руб."
+				}
+			}
+
+			if err := bot.SendMessage(chat, text); err != nil {
+				log.Fatal(err)
+			}
+		} else if strings.HasPrefix(update.CallbackQuery.Data, "operation:") {
+			if operation, err := utils.GetOperation(
+				ctx,
+				&db,
+				chat,
+				strings.TrimPrefix(update.CallbackQuery.Data, "operation:"),
+			); err != nil {
+				log.Println(err)
+
+				if utils.IsUserError(err) {
+					err = bot.SendMessage(chat, err.Error())
+				} else {
+					err = bot.SendMessage(chat, enums.UserErrors[enums.UNEXPECTED_ERROR])
+				}
+				if err != nil {
+					log.Fatal(err)
+				}
+			} else {
+				if !operation.Reverted && operation.Reverts == "" && !operation.Pending &&
+					checkOperationRole(operation, chat, update.CallbackQuery.From.UserId, enums.CONTRIBUTOR) == nil {
+					bot.InlineKeyboard.Create([]models.InlineKeyboardButton{
+						{
+							Text:         enums.OperationFieldNames[enums.AMOUNT],
+							CallbackData: "edit:" + enums.OperationFields[enums.AMOUNT] + ":" + operation.Id,
+						},
+						{
+							Text:         enums.OperationFieldNames[enums.COMMENT],
+							CallbackData: "edit:" + enums.OperationFields[enums.COMMENT] + ":" + operation.Id,
+						},
+					})
+					bot.InlineKeyboard.Create([]models.InlineKeyboardButton{
+						{
+							Text:         enums.OperationFieldNames[enums.BANK],
+							CallbackData: "edit:" + enums.OperationFields[enums.BANK] + ":" + operation.Id,
+						},
+						{
+							Text:         enums.OperationFieldNames[enums.DATE],
+							CallbackData: "edit:" + enums.OperationFields[enums.DATE] + ":" + operation.Id,
+						},
+					})
+					bot.InlineKeyboard.Create([]models.InlineKeyboardButton{{
+						Text:         "Удалить",
+						CallbackData: "delete:" + operation.Id,
+					}})
+				}
+
+				if err = bot.SendMessage(chat, utils.GetOperationDescription(ctx, &db, operation)); err != nil {
+					log.Fatal(err)
+				}
+
+				bot.InlineKeyboard.Destroy()
+			}
+		} else if strings.HasPrefix(update.CallbackQuery.Data, "edit:") {
+			data := strings.SplitN(update.CallbackQuery.Data, ":", 3)
+
+			operation, err := utils.GetOperation(ctx, &db, chat, data[len(data)-1])
+			if err == nil {
+				err = checkOperationRole(operation, chat, update.CallbackQuery.From.UserId, enums.CONTRIBUTOR)
+			}
+			if err == nil && operation.Pending {
+				err = errors.New(enums.UserErrors[enums.OPERATION_IS_PENDING])
+			}
+
+			if err != nil {
+				log.Println(err)
+
+				if utils.IsUserError(err) {
+					err = bot.SendMessage(chat, err.Error())
+				} else {
+					err = bot.SendMessage(chat, enums.UserErrors[enums.UNEXPECTED_ERROR])
+				}
+				if err != nil {
+					log.Fatal(err)
+				}
+			} else {
+				var text string
+				var keyboard []string
+
+				switch data[1] {
+				case enums.OperationFields[enums.AMOUNT]:
+					text = "Введи новую сумму"
+				case enums.OperationFields[enums.COMMENT]:
+					text = "Введи новый комментарий"
+				case enums.OperationFields[enums.DATE]:
+					text = "Введи новую дату в формате ДД.ММ.ГГГГ или ДД.ММ.ГГГГ ЧЧ:ММ"
+				case enums.OperationFields[enums.BANK]:
+					text = "В какую копилку перенести операцию?"
+
+					if keyboard, err = utils.GetBankNames(ctx, &db, chat); err != nil {
+						log.Println(err)
+					}
+				}
+
+				bot.ReplyKeyboard.Create(keyboard)
+
+				if err = bot.SendMessage(chat, text+". Напиши /cancel, если передумал"); err != nil {
+					log.Fatal(err)
+				}
+
+				bot.ReplyKeyboard.Destroy()
+				processing.Create(
+					chat,
+					update.CallbackQuery.From.UserId,
+					models.Command{Name: enums.EDIT_OPERATION},
+					models.Extra{
+						Operation: *operation,
+						Field:     data[1],
+						Keyboard:  keyboard,
+					},
+				)
+			}
+		} else if strings.HasPrefix(update.CallbackQuery.Data, "delete:") {
+			bot.InlineKeyboard.Create([]models.InlineKeyboardButton{{
+				Text:         "Да, удалить",
+				CallbackData: "destroy:" + strings.TrimPrefix(update.CallbackQuery.Data, "delete:"),
+			}})
+
+			if err := bot.SendMessage(chat, "Точно удалить операцию? Балансы копилок будут пересчитаны"); err != nil {
+				log.Fatal(err)
+			}
+
+			bot.InlineKeyboard.Destroy()
+		} else if strings.HasPrefix(update.CallbackQuery.Data, "destroy:") {
+			text := "Операция удалена!%0A"
+
+			if operation, err := utils.GetOperation(
+				ctx,
+				&db,
+				chat,
+				strings.TrimPrefix(update.CallbackQuery.Data, "destroy:"),
+			); err != nil {
+				log.Println(err)
+
+				text = enums.UserErrors[enums.UNEXPECTED_ERROR]
+				if utils.IsUserError(err) {
+					text = err.Error()
+				}
+			} else if err = checkOperationRole(operation, chat, update.CallbackQuery.From.UserId, enums.CONTRIBUTOR); err != nil {
+				log.Println(err)
+
+				text = enums.UserErrors[enums.UNEXPECTED_ERROR]
+				if utils.IsUserError(err) {
+					text = err.Error()
+				}
+			} else if banks, err := utils.DestroyOperation(ctx, &db, operation); err != nil {
+				log.Println(err)
+
+				text = enums.UserErrors[enums.UNEXPECTED_ERROR]
+				if utils.IsUserError(err) {
+					text = err.Error()
+				}
+			} else {
+				for _, bank := range banks {
+					text += "%0AБаланс копилки " + bank.Name + " составляет " + strconv.Itoa(bank.Balance) + " руб."
+				}
+			}
+
+			if err := bot.SendMessage(chat, text); err != nil {
+				log.Fatal(err)
+			}
+		} else if strings.HasPrefix(update.CallbackQuery.Data, "approve:") ||
+			strings.HasPrefix(update.CallbackQuery.Data, "reject:") {
+			data := strings.SplitN(update.CallbackQuery.Data, ":", 2)
+
+			var text string
+
+			if operation, err := utils.GetPendingOperation(ctx, &db, data[1]); err != nil {
+				log.Println(err)
+
+				text = enums.UserErrors[enums.UNEXPECTED_ERROR]
+				if utils.IsUserError(err) {
+					text = err.Error()
+				}
+			} else if bank, err := utils.GetBankById(ctx, &db, operation.Account, operation.Bank); err != nil {
+				log.Println(err)
+
+				text = enums.UserErrors[enums.UNEXPECTED_ERROR]
+			} else if !isApprover(bank, update.CallbackQuery.From.UserId) {
+				text = enums.UserErrors[enums.NOT_APPROVER]
+			} else {
+				result := "одобрен"
+
+				if data[0] == "approve" {
+					bank, err = utils.ApproveOperation(ctx, &db, operation, &update.CallbackQuery.From)
+				} else {
+					result = "отклонён"
+					err = utils.RejectOperation(ctx, &db, operation)
+				}
+
+				if err != nil {
+					log.Println(err)
+
+					text = enums.UserErrors[enums.UNEXPECTED_ERROR]
+					if utils.IsUserError(err) {
+						text = err.Error()
+					}
+				} else {
+					description := utils.GetOperationDescription(ctx, &db, operation)
+
+					if err = bot.EditMessage(
+						chat,
+						update.CallbackQuery.Message.MessagId,
+						"Запрос "+result+"%0A%0A"+description,
+					); err != nil {
+						log.Println(err)
+					}
+
+					if operation.Author != nil && operation.Author.UserId != update.CallbackQuery.From.UserId {
+						if err = bot.SendMessage(
+							operation.Author.UserId,
+							url.QueryEscape(update.CallbackQuery.From.Name())+" · твой запрос "+result+
+								". Текущий баланс копилки "+bank.Name+": "+strconv.Itoa(bank.Balance)+" руб.%0A%0A"+description,
+						); err != nil {
+							log.Println(err)
+						}
+					}
+				}
+			}
+
+			if text != "" {
+				if err := bot.SendMessage(chat, text); err != nil {
+					log.Fatal(err)
+				}
+			}
+		} else if strings.HasPrefix(update.CallbackQuery.Data, "settle:") {
+			// only the transfers shown in the message are settled, so expenses added after /settle stay unpaid
+			if err := utils.SettleSplits(
+				ctx,
+				&db,
+				chat,
+				strings.TrimPrefix(update.CallbackQuery.Data, "settle:"),
+				&update.CallbackQuery.From,
+			); err != nil {
+				log.Println(err)
+
+				if utils.IsUserError(err) {
+					err = bot.SendMessage(chat, err.Error())
+				} else {
+					err = bot.SendMessage(chat, enums.UserErrors[enums.UNEXPECTED_ERROR])
+				}
+				if err != nil {
+					log.Fatal(err)
+				}
+			} else if err = bot.EditMessage(
+				chat,
+				update.CallbackQuery.Message.MessagId,
+				url.QueryEscape(update.CallbackQuery.From.Name())+
+					" отметил, что переводы из этого сообщения выполнены. Текущие балансы покажет команда /settle",
+			); err != nil {
+				log.Println(err)
+			}
+		}
+
+		if err := bot.AnswerCallbackQuery(update.CallbackQuery.CallbackQueryId, ""); err != nil {
+			log.Println(err)
+		}
+		// --------------------------------------------------------------------------------------------------------
+	} else if update.Message.Text == enums.BotCommands[enums.START] {
 		// ---------------------------------------------------------------------------------- handle /start command
-		processing.Destroy(update.Message.Chat.ChatId)
+		processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
 
 		if _, err := utils.GetBankNames(ctx, &db, update.Message.Chat.ChatId); err != nil {
 			log.Println(err)
@@ -28,6 +386,7 @@ func handler(update models.Update) {
 
 				processing.Create(
 					update.Message.Chat.ChatId,
+					update.Message.From.UserId,
 					models.Command{Name: enums.CREATE_BANK},
 					models.Extra{},
 				)
@@ -46,15 +405,84 @@ func handler(update models.Update) {
 					"/income - увеличить баланс копилки%0A"+
 					"/expense - уменьшить баланс копилки%0A"+
 					"/create_transfer - создать перевод%0A"+
-					"/get_balance - узнать баланс копилки",
+					"/get_balance - узнать баланс копилки%0A"+
+					"/set_goal - поставить цель копилке%0A"+
+					"/set_limit - установить лимит расходов копилки%0A"+
+					"/get_limits - узнать состояние лимитов%0A"+
+					"/set_overdraft - настроить уход копилки в минус%0A"+
+					"/create_schedule - создать регулярную операцию%0A"+
+					"/schedules - список регулярных операций%0A"+
+					"/pause_schedule - приостановить или возобновить регулярную операцию%0A"+
+					"/edit_schedule - изменить регулярную операцию%0A"+
+					"/destroy_schedule - удалить регулярную операцию%0A"+
+					"/digest - настроить ежедневную или еженедельную сводку%0A"+
+					"/history - история операций%0A"+
+					"/undo - отменить последнюю операцию%0A"+
+					"/report - отчёт о доходах и расходах за период%0A"+
+					"/search - найти операции по комментарию%0A"+
+					"/rename_bank - переименовать копилку%0A"+
+					"/merge_banks - объединить две копилки%0A"+
+					"/move_bank - вложить копилку в другую%0A"+
+					"/export - выгрузить операции в CSV или XLSX%0A"+
+					"/ledger_accounts - счета доходов и расходов для hledger и beancount%0A"+
+					"/chart - графики баланса, расходов, доходов и расходов по месяцам%0A"+
+					"/share_bank - поделиться копилкой с другим пользователем%0A"+
+					"/create_debt - записать долг%0A"+
+					"/repay_debt - записать возврат долга%0A"+
+					"/debts - список долгов%0A"+
+					"/split - разделить общий расход в группе%0A"+
+					"/settle - кто кому сколько должен перевести",
+			); err != nil {
+				log.Fatal(err)
+			}
+		}
+		// --------------------------------------------------------------------------------------------------------
+	} else if strings.HasPrefix(update.Message.Text, enums.BotCommands[enums.START]+" ") {
+		// ------------------------------------------------------------------------------------- handle invite link
+		processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+
+		member := models.Member{
+			Account: update.Message.Chat.ChatId,
+			Name:    update.Message.From.Name(),
+		}
+
+		if bank, err := utils.AcceptInvite(
+			ctx,
+			&db,
+			strings.TrimSpace(strings.TrimPrefix(update.Message.Text, enums.BotCommands[enums.START])),
+			member,
+		); err != nil {
+			log.Println(err)
+
+			if utils.IsUserError(err) {
+				err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+			} else {
+				err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+			}
+			if err != nil {
+				log.Fatal(err)
+			}
+		} else {
+			role := enums.RoleNames[utils.GetRole(bank, update.Message.Chat.ChatId)]
+
+			if err = bot.SendMessage(
+				update.Message.Chat.ChatId,
+				"Теперь тебе доступна копилка "+bank.Name+". Твоя роль: "+role+
+					". Копилка появится в списке копилок во всех командах",
 			); err != nil {
 				log.Fatal(err)
 			}
+
+			notifyMembers(
+				bank,
+				update.Message.Chat.ChatId,
+				url.QueryEscape(member.Name)+" · новый участник копилки "+bank.Name+", роль: "+role,
+			)
 		}
 		// --------------------------------------------------------------------------------------------------------
 	} else if update.Message.Text == enums.BotCommands[enums.CANCEL] {
 		// --------------------------------------------------------------------------------- handle /cancel command
-		processing.Destroy(update.Message.Chat.ChatId)
+		processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
 
 		if err := bot.SendMessage(
 			update.Message.Chat.ChatId,
@@ -65,7 +493,7 @@ func handler(update models.Update) {
 		// --------------------------------------------------------------------------------------------------------
 	} else if update.Message.Text == enums.BotCommands[enums.CREATE_BANK] {
 		// ---------------------------------------------------------------------------- handle /create_bank command
-		processing.Destroy(update.Message.Chat.ChatId)
+		processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
 
 		if err := bot.SendMessage(
 			update.Message.Chat.ChatId,
@@ -76,13 +504,14 @@ func handler(update models.Update) {
 
 		processing.Create(
 			update.Message.Chat.ChatId,
+			update.Message.From.UserId,
 			models.Command{Name: enums.CREATE_BANK},
 			models.Extra{},
 		)
 		// --------------------------------------------------------------------------------------------------------
 	} else if update.Message.Text == enums.BotCommands[enums.DESTROY_BANK] {
 		// --------------------------------------------------------------------------- handle /destroy_bank command
-		processing.Destroy(update.Message.Chat.ChatId)
+		processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
 
 		if bankNames, err := utils.GetBankNames(ctx, &db, update.Message.Chat.ChatId); err != nil {
 			bot.SendMessage(update.Message.Chat.ChatId, err.Error())
@@ -99,6 +528,7 @@ func handler(update models.Update) {
 			bot.ReplyKeyboard.Destroy()
 			processing.Create(
 				update.Message.Chat.ChatId,
+				update.Message.From.UserId,
 				models.Command{Name: enums.DESTROY_BANK},
 				models.Extra{Keyboard: bankNames},
 			)
@@ -121,6 +551,7 @@ func handler(update models.Update) {
 			bot.ReplyKeyboard.Destroy()
 			processing.Create(
 				update.Message.Chat.ChatId,
+				update.Message.From.UserId,
 				models.Command{Name: enums.GET_BALANCE},
 				models.Extra{Keyboard: bankNames},
 			)
@@ -143,6 +574,7 @@ func handler(update models.Update) {
 			bot.ReplyKeyboard.Destroy()
 			processing.Create(
 				update.Message.Chat.ChatId,
+				update.Message.From.UserId,
 				models.Command{Name: enums.INCOME},
 				models.Extra{
 					Keyboard: bankNames,
@@ -167,6 +599,7 @@ func handler(update models.Update) {
 			bot.ReplyKeyboard.Destroy()
 			processing.Create(
 				update.Message.Chat.ChatId,
+				update.Message.From.UserId,
 				models.Command{Name: enums.EXPENSE},
 				models.Extra{
 					Keyboard: bankNames,
@@ -190,105 +623,4309 @@ func handler(update models.Update) {
 			bot.ReplyKeyboard.Destroy()
 			processing.Create(
 				update.Message.Chat.ChatId,
+				update.Message.From.UserId,
 				models.Command{Name: enums.CREATE_TRANSFER},
 				models.Extra{
 					Keyboard: bankNames,
 				},
 			)
 		}
-	} else {
-		var process models.Process
+	} else if update.Message.Text == enums.BotCommands[enums.SET_GOAL] {
+		// ------------------------------------------------------------------------------- handle /set_goal command
+		if bankNames, err := utils.GetBankNames(ctx, &db, update.Message.Chat.ChatId); err != nil {
+			bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+		} else {
+			bot.ReplyKeyboard.Create(bankNames)
 
-		for _, proc := range processing.Processes {
-			if proc.Chat == update.Message.Chat.ChatId {
-				process = proc
+			if err = bot.SendMessage(
+				update.Message.Chat.ChatId,
+				"Для какой копилки поставим цель? Напиши /cancel, если передумал",
+			); err != nil {
+				log.Fatal(err)
 			}
+
+			bot.ReplyKeyboard.Destroy()
+			processing.Create(
+				update.Message.Chat.ChatId,
+				update.Message.From.UserId,
+				models.Command{Name: enums.SET_GOAL},
+				models.Extra{
+					Keyboard: bankNames,
+				},
+			)
 		}
+		// --------------------------------------------------------------------------------------------------------
+	} else if update.Message.Text == enums.BotCommands[enums.SET_LIMIT] {
+		// ------------------------------------------------------------------------------ handle /set_limit command
+		if bankNames, err := utils.GetBankNames(ctx, &db, update.Message.Chat.ChatId); err != nil {
+			bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+		} else {
+			bot.ReplyKeyboard.Create(bankNames)
 
-		if process.Command.Name == enums.UndefinedBotCommand {
-			// -------------------------------------------------------------------------- handle unexpected message
-			if err := bot.SendMessage(
+			if err = bot.SendMessage(
 				update.Message.Chat.ChatId,
-				"Для работы с ботом используй одну из следующих команд:%0A"+
-					"/create_bank - создать копилку%0A"+
-					"/destroy_bank - удалить копилку%0A"+
-					"/income - увеличить баланс копилки%0A"+
-					"/expense - уменьшить баланс копилки%0A"+
-					"/create_transfer - создать перевод%0A"+
-					"/get_balance - узнать баланс копилки%0A",
+				"Для какой копилки установим лимит? Напиши /cancel, если передумал",
 			); err != nil {
 				log.Fatal(err)
 			}
-			// -----------------------------------------------------------------------------------------------------
-		} else if process.Command.Name == enums.CREATE_BANK {
-			// ---------------------------------------------------- handle update in /create_bank command processing
-			bank, err := utils.GetBank(ctx, &db, update.Message.Chat.ChatId, update.Message.Text)
-			if err != nil && err.Error() != enums.UserErrors[enums.BANK_NOT_FOUND] {
-				log.Println(err)
 
-				err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
-				if err != nil {
-					log.Fatal(err)
-				}
+			bot.ReplyKeyboard.Destroy()
+			processing.Create(
+				update.Message.Chat.ChatId,
+				update.Message.From.UserId,
+				models.Command{Name: enums.SET_LIMIT},
+				models.Extra{
+					Keyboard: bankNames,
+				},
+			)
+		}
+		// --------------------------------------------------------------------------------------------------------
+	} else if update.Message.Text == enums.BotCommands[enums.GET_LIMITS] {
+		// ----------------------------------------------------------------------------- handle /get_limits command
+		processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
 
-				processing.Destroy(update.Message.Chat.ChatId)
-			} else if bank != nil {
-				log.Println(err)
+		if banks, err := utils.GetBanks(ctx, &db, update.Message.Chat.ChatId); err != nil {
+			bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+		} else {
+			var text string
 
-				err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.BANK_NAME_IS_EXIST])
-				if err != nil {
-					log.Fatal(err)
-				}
-			} else {
-				bank = &models.Bank{
-					Account: update.Message.Chat.ChatId,
-					Name:    update.Message.Text,
+			for index := range banks {
+				if banks[index].Limit <= 0 {
+					continue
 				}
 
-				if err = bank.Create(ctx, &db); err != nil {
+				from := utils.GetPeriodStart(banks[index].Period, time.Now())
+
+				spent, err := utils.GetSpent(ctx, &db, &banks[index], from)
+				if err != nil {
 					log.Println(err)
 
 					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
 					if err != nil {
 						log.Fatal(err)
 					}
-				} else {
-					if err = bot.SendMessage(
-						update.Message.Chat.ChatId,
-						"Копилка успешно создана!",
-					); err != nil {
-						log.Fatal(err)
-					}
+
+					return
 				}
 
-				processing.Destroy(update.Message.Chat.ChatId)
+				text += "Копилка " + url.QueryEscape(banks[index].Name) + "%0A" + utils.GetLimitProgress(&banks[index], spent) + "%0A%0A"
 			}
-			// -------------------------------------------------------------------------------------------------
-		} else if process.Command.Name == enums.DESTROY_BANK {
-			// ----------------------------------------------- handle update in /destroy_bank command processing
-			if bank, err := utils.GetBank(ctx, &db, update.Message.Chat.ChatId, update.Message.Text); err != nil {
-				log.Println(err)
-
-				if err.Error() == enums.UserErrors[enums.BANK_NOT_FOUND] {
-					bot.ReplyKeyboard.Create(process.Extra.Keyboard)
 
-					err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
-					if err != nil {
-						log.Fatal(err)
-					}
+			if text == "" {
+				text = enums.UserErrors[enums.NO_LIMITS]
+			}
 
-					bot.ReplyKeyboard.Destroy()
-				} else {
-					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+			if err = bot.SendMessage(update.Message.Chat.ChatId, text); err != nil {
+				log.Fatal(err)
+			}
+		}
+		// --------------------------------------------------------------------------------------------------------
+	} else if update.Message.Text == enums.BotCommands[enums.SET_OVERDRAFT] {
+		// -------------------------------------------------------------------------- handle /set_overdraft command
+		if bankNames, err := utils.GetBankNames(ctx, &db, update.Message.Chat.ChatId); err != nil {
+			bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+		} else {
+			bot.ReplyKeyboard.Create(bankNames)
+
+			if err = bot.SendMessage(
+				update.Message.Chat.ChatId,
+				"Для какой копилки настроим уход в минус? Напиши /cancel, если передумал",
+			); err != nil {
+				log.Fatal(err)
+			}
+
+			bot.ReplyKeyboard.Destroy()
+			processing.Create(
+				update.Message.Chat.ChatId,
+				update.Message.From.UserId,
+				models.Command{Name: enums.SET_OVERDRAFT},
+				models.Extra{
+					Keyboard: bankNames,
+				},
+			)
+		}
+		// --------------------------------------------------------------------------------------------------------
+	} else if update.Message.Text == enums.BotCommands[enums.CREATE_SCHEDULE] {
+		// ------------------------------------------------------------------------ handle /create_schedule command
+		if bankNames, err := utils.GetBankNames(ctx, &db, update.Message.Chat.ChatId); err != nil {
+			bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+		} else {
+			operations := []string{
+				enums.OperationNames[enums.INCOME],
+				enums.OperationNames[enums.EXPENSE],
+				enums.OperationNames[enums.CREATE_TRANSFER],
+			}
+
+			bot.ReplyKeyboard.Create(operations)
+
+			if err = bot.SendMessage(
+				update.Message.Chat.ChatId,
+				"Какую операцию будем повторять? Напиши /cancel, если передумал",
+			); err != nil {
+				log.Fatal(err)
+			}
+
+			bot.ReplyKeyboard.Destroy()
+			processing.Create(
+				update.Message.Chat.ChatId,
+				update.Message.From.UserId,
+				models.Command{Name: enums.CREATE_SCHEDULE},
+				models.Extra{
+					Keyboard: bankNames,
+				},
+			)
+		}
+		// --------------------------------------------------------------------------------------------------------
+	} else if update.Message.Text == enums.BotCommands[enums.GET_SCHEDULES] {
+		// ------------------------------------------------------------------------------ handle /schedules command
+		processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+
+		if schedules, labels, err := utils.GetSchedules(ctx, &db, update.Message.Chat.ChatId); err != nil {
+			bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+		} else {
+			text := "Твои регулярные операции:%0A%0A"
+
+			for index := range schedules {
+				text += labels[index][:strings.Index(labels[index], " ")] + " " +
+					utils.GetScheduleDescription(ctx, &db, &schedules[index]) + "%0A%0A"
+			}
+
+			if err = bot.SendMessage(update.Message.Chat.ChatId, text); err != nil {
+				log.Fatal(err)
+			}
+		}
+		// --------------------------------------------------------------------------------------------------------
+	} else if update.Message.Text == enums.BotCommands[enums.PAUSE_SCHEDULE] ||
+		update.Message.Text == enums.BotCommands[enums.EDIT_SCHEDULE] ||
+		update.Message.Text == enums.BotCommands[enums.DESTROY_SCHEDULE] {
+		// ----------------------------------- handle /pause_schedule, /edit_schedule and /destroy_schedule command
+		var command enums.BotCommand
+
+		for key, value := range enums.BotCommands {
+			if value == update.Message.Text {
+				command = key
+			}
+		}
+
+		if _, labels, err := utils.GetSchedules(ctx, &db, update.Message.Chat.ChatId); err != nil {
+			bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+		} else {
+			bot.ReplyKeyboard.Create(labels)
+
+			if err = bot.SendMessage(
+				update.Message.Chat.ChatId,
+				"Выбери регулярную операцию. Напиши /cancel, если передумал",
+			); err != nil {
+				log.Fatal(err)
+			}
+
+			bot.ReplyKeyboard.Destroy()
+			processing.Create(
+				update.Message.Chat.ChatId,
+				update.Message.From.UserId,
+				models.Command{Name: command},
+				models.Extra{
+					Keyboard: labels,
+				},
+			)
+		}
+		// --------------------------------------------------------------------------------------------------------
+	} else if update.Message.Text == enums.BotCommands[enums.SET_DIGEST] {
+		// --------------------------------------------------------------------------------- handle /digest command
+		digests := []string{"Ежедневно", "Еженедельно", "Отключить"}
+
+		bot.ReplyKeyboard.Create(digests)
+
+		if err := bot.SendMessage(
+			update.Message.Chat.ChatId,
+			"Как часто присылать сводку по копилкам? Напиши /cancel, если передумал",
+		); err != nil {
+			log.Fatal(err)
+		}
+
+		bot.ReplyKeyboard.Destroy()
+		processing.Create(
+			update.Message.Chat.ChatId,
+			update.Message.From.UserId,
+			models.Command{Name: enums.SET_DIGEST},
+			models.Extra{
+				Keyboard: digests,
+			},
+		)
+		// --------------------------------------------------------------------------------------------------------
+	} else if update.Message.Text == enums.BotCommands[enums.GET_HISTORY] {
+		// -------------------------------------------------------------------------------- handle /history command
+		if bankNames, err := utils.GetBankNames(ctx, &db, update.Message.Chat.ChatId); err != nil {
+			bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+		} else {
+			keyboard := append([]string{"Все копилки"}, bankNames...)
+
+			bot.ReplyKeyboard.Create(keyboard)
+
+			if err = bot.SendMessage(
+				update.Message.Chat.ChatId,
+				"Историю какой копилки показать? Напиши /cancel, если передумал",
+			); err != nil {
+				log.Fatal(err)
+			}
+
+			bot.ReplyKeyboard.Destroy()
+			processing.Create(
+				update.Message.Chat.ChatId,
+				update.Message.From.UserId,
+				models.Command{Name: enums.GET_HISTORY},
+				models.Extra{
+					Keyboard: keyboard,
+				},
+			)
+		}
+		// --------------------------------------------------------------------------------------------------------
+	} else if update.Message.Text == enums.BotCommands[enums.UNDO] {
+		// ----------------------------------------------------------------------------------- handle /undo command
+		processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+
+		since := time.Now().Add(-undoWindow)
+
+		if operation, err := utils.GetLastOperation(ctx, &db, update.Message.From.UserId, since); err != nil {
+			log.Println(err)
+
+			if utils.IsUserError(err) {
+				err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+			} else {
+				err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+			}
+			if err != nil {
+				log.Fatal(err)
+			}
+		} else if err = checkOperationRole(
+			operation,
+			update.Message.Chat.ChatId,
+			update.Message.From.UserId,
+			enums.CONTRIBUTOR,
+		); err != nil {
+			log.Println(err)
+
+			if utils.IsUserError(err) {
+				err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+			} else {
+				err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+			}
+			if err != nil {
+				log.Fatal(err)
+			}
+		} else if banks, err := utils.UndoOperation(ctx, &db, operation, since); err != nil {
+			log.Println(err)
+
+			if utils.IsUserError(err) {
+				err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+			} else {
+				err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+			}
+			if err != nil {
+				log.Fatal(err)
+			}
+		} else {
+			text := "Операция на " + strconv.Itoa(operation.Amount) + " руб. отменена!%0A"
+
+			for _, bank := range banks {
+				text += "%0AБаланс копилки " + bank.Name + " составляет " + strconv.Itoa(bank.Balance) + " руб."
+			}
+
+			if err = bot.SendMessage(update.Message.Chat.ChatId, text); err != nil {
+				log.Fatal(err)
+			}
+		}
+		// --------------------------------------------------------------------------------------------------------
+	} else if update.Message.Text == enums.BotCommands[enums.GET_REPORT] {
+		// --------------------------------------------------------------------------------- handle /report command
+		periods := []string{
+			enums.PeriodFilters[enums.THIS_MONTH],
+			enums.PeriodFilters[enums.LAST_MONTH],
+			enums.PeriodFilters[enums.THIS_WEEK],
+		}
+
+		bot.ReplyKeyboard.Create(periods)
+
+		if err := bot.SendMessage(
+			update.Message.Chat.ChatId,
+			"За какой период составить отчёт? Выбери его, напиши месяц в формате ММ.ГГГГ "+
+				"или период в формате ДД.ММ.ГГГГ-ДД.ММ.ГГГГ. Напиши /cancel, если передумал",
+		); err != nil {
+			log.Fatal(err)
+		}
+
+		bot.ReplyKeyboard.Destroy()
+		processing.Create(
+			update.Message.Chat.ChatId,
+			update.Message.From.UserId,
+			models.Command{Name: enums.GET_REPORT},
+			models.Extra{
+				Keyboard: periods,
+			},
+		)
+		// --------------------------------------------------------------------------------------------------------
+	} else if update.Message.Text == enums.BotCommands[enums.SEARCH] ||
+		strings.HasPrefix(update.Message.Text, enums.BotCommands[enums.SEARCH]+" ") {
+		// --------------------------------------------------------------------------------- handle /search command
+		query := strings.TrimSpace(strings.TrimPrefix(update.Message.Text, enums.BotCommands[enums.SEARCH]))
+
+		if query == "" {
+			if err := bot.SendMessage(
+				update.Message.Chat.ChatId,
+				"Что будем искать в комментариях к операциям? Напиши /cancel, если передумал",
+			); err != nil {
+				log.Fatal(err)
+			}
+
+			processing.Create(
+				update.Message.Chat.ChatId,
+				update.Message.From.UserId,
+				models.Command{Name: enums.SEARCH},
+				models.Extra{},
+			)
+		} else {
+			processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+
+			if result, err := utils.GetSearchResult(ctx, &db, update.Message.Chat.ChatId, query); err != nil {
+				log.Println(err)
+
+				err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+				if err != nil {
+					log.Fatal(err)
+				}
+			} else {
+				if err = bot.SendMessage(update.Message.Chat.ChatId, result); err != nil {
+					log.Fatal(err)
+				}
+			}
+		}
+		// --------------------------------------------------------------------------------------------------------
+	} else if update.Message.Text == enums.BotCommands[enums.RENAME_BANK] {
+		// ---------------------------------------------------------------------------- handle /rename_bank command
+		processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+
+		if bankNames, err := utils.GetBankNames(ctx, &db, update.Message.Chat.ChatId); err != nil {
+			bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+		} else {
+			bot.ReplyKeyboard.Create(bankNames)
+
+			if err = bot.SendMessage(
+				update.Message.Chat.ChatId,
+				"Какую копилку ты хочешь переименовать? Напиши /cancel, если передумал",
+			); err != nil {
+				log.Fatal(err)
+			}
+
+			bot.ReplyKeyboard.Destroy()
+			processing.Create(
+				update.Message.Chat.ChatId,
+				update.Message.From.UserId,
+				models.Command{Name: enums.RENAME_BANK},
+				models.Extra{Keyboard: bankNames},
+			)
+		}
+		// --------------------------------------------------------------------------------------------------------
+	} else if update.Message.Text == enums.BotCommands[enums.MERGE_BANKS] {
+		// ---------------------------------------------------------------------------- handle /merge_banks command
+		processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+
+		if bankNames, err := utils.GetBankNames(ctx, &db, update.Message.Chat.ChatId); err != nil {
+			bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+		} else {
+			bot.ReplyKeyboard.Create(bankNames)
+
+			if err = bot.SendMessage(
+				update.Message.Chat.ChatId,
+				"Какую копилку ты хочешь присоединить к другой? Она будет удалена, а её баланс и операции перейдут "+
+					"в выбранную копилку. Напиши /cancel, если передумал",
+			); err != nil {
+				log.Fatal(err)
+			}
+
+			bot.ReplyKeyboard.Destroy()
+			processing.Create(
+				update.Message.Chat.ChatId,
+				update.Message.From.UserId,
+				models.Command{Name: enums.MERGE_BANKS},
+				models.Extra{Keyboard: bankNames},
+			)
+		}
+		// --------------------------------------------------------------------------------------------------------
+	} else if update.Message.Text == enums.BotCommands[enums.MOVE_BANK] {
+		// ------------------------------------------------------------------------------ handle /move_bank command
+		processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+
+		if bankNames, err := utils.GetBankNames(ctx, &db, update.Message.Chat.ChatId); err != nil {
+			bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+		} else {
+			bot.ReplyKeyboard.Create(bankNames)
+
+			if err = bot.SendMessage(
+				update.Message.Chat.ChatId,
+				"Какую копилку ты хочешь переместить? Напиши /cancel, если передумал",
+			); err != nil {
+				log.Fatal(err)
+			}
+
+			bot.ReplyKeyboard.Destroy()
+			processing.Create(
+				update.Message.Chat.ChatId,
+				update.Message.From.UserId,
+				models.Command{Name: enums.MOVE_BANK},
+				models.Extra{Keyboard: bankNames},
+			)
+		}
+		// --------------------------------------------------------------------------------------------------------
+	} else if utils.IsQuickEntry(update.Message.Text) &&
+		(strings.HasPrefix(update.Message.Text, "/") || !processing.Exists(update.Message.Chat.ChatId, update.Message.From.UserId)) {
+		// ------------------------------------------------------------------------------------- handle quick entry
+		if operation, bank, err := utils.ParseQuickEntry(
+			ctx,
+			&db,
+			update.Message.Chat.ChatId,
+			update.Message.Text,
+		); err != nil {
+			bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+		} else if bankNames, err := utils.GetBankNames(ctx, &db, update.Message.Chat.ChatId); err != nil {
+			bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+		} else {
+			command := enums.INCOME
+			if operation.Operation == enums.BotCommands[enums.EXPENSE] {
+				command = enums.EXPENSE
+			}
+
+			processing.Create(
+				update.Message.Chat.ChatId,
+				update.Message.From.UserId,
+				models.Command{Name: command},
+				models.Extra{
+					Keyboard:  bankNames,
+					Operation: *operation,
+				},
+			)
+
+			if bank != nil {
+				update.Message.Text = bank.Name
+				handler(update)
+			} else {
+				bot.ReplyKeyboard.Create(bankNames)
+
+				if err = bot.SendMessage(
+					update.Message.Chat.ChatId,
+					"Баланс какой копилки будем изменять? Напиши /cancel, если передумал",
+				); err != nil {
+					log.Fatal(err)
+				}
+
+				bot.ReplyKeyboard.Destroy()
+			}
+		}
+		// --------------------------------------------------------------------------------------------------------
+	} else if utils.IsReceipt(update.Message.Text) || len(update.Message.Photo) > 0 {
+		// ----------------------------------------------------------------------------------------- handle receipt
+		processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+
+		text := update.Message.Text
+
+		var err error
+
+		if len(update.Message.Photo) > 0 {
+			var photo []byte
+
+			// the last size is the largest one, which gives the decoder the best chance
+			if photo, err = bot.GetFile(update.Message.Photo[len(update.Message.Photo)-1].FileId); err == nil {
+				text, err = utils.DecodeQR(photo)
+			}
+		}
+
+		var operation *models.Operation
+		if err == nil {
+			operation, err = utils.GetReceiptOperation(ctx, &db, update.Message.Chat.ChatId, text)
+		}
+
+		var bankNames []string
+		if err == nil {
+			bankNames, err = utils.GetBankNames(ctx, &db, update.Message.Chat.ChatId)
+		}
+
+		if err != nil {
+			log.Println(err)
+
+			if !utils.IsUserError(err) {
+				err = errors.New(enums.UserErrors[enums.UNEXPECTED_ERROR])
+			}
+
+			if err = bot.SendMessage(update.Message.Chat.ChatId, err.Error()); err != nil {
+				log.Fatal(err)
+			}
+		} else {
+			bot.ReplyKeyboard.Create(bankNames)
+
+			if err = bot.SendMessage(
+				update.Message.Chat.ChatId,
+				"Чек на "+strconv.Itoa(operation.Amount)+" руб. от "+
+					utils.ParseCreatedAt(operation.CreatedAt).Format("02.01.2006 15:04")+
+					". Из какой копилки он оплачен? Напиши /cancel, если передумал",
+			); err != nil {
+				log.Fatal(err)
+			}
+
+			bot.ReplyKeyboard.Destroy()
+			processing.Create(
+				update.Message.Chat.ChatId,
+				update.Message.From.UserId,
+				models.Command{Name: enums.EXPENSE},
+				models.Extra{
+					Keyboard:  bankNames,
+					Operation: *operation,
+				},
+			)
+		}
+		// --------------------------------------------------------------------------------------------------------
+	} else if update.Message.Document.FileId != "" {
+		// ---------------------------------------------------------------------------------------- handle document
+		processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+
+		var records [][]string
+		var err error
+
+		if !strings.HasSuffix(strings.ToLower(update.Message.Document.FileName), ".csv") {
+			err = errors.New(enums.UserErrors[enums.UNSUPPORTED_FILE])
+		} else if data, fileErr := bot.GetFile(update.Message.Document.FileId); fileErr != nil {
+			err = fileErr
+		} else {
+			records, err = utils.ReadCSV(data)
+		}
+
+		if err != nil {
+			log.Println(err)
+
+			if !utils.IsUserError(err) {
+				err = errors.New(enums.UserErrors[enums.UNEXPECTED_ERROR])
+			}
+
+			if err = bot.SendMessage(update.Message.Chat.ChatId, err.Error()); err != nil {
+				log.Fatal(err)
+			}
+		} else if columns, ok := utils.DetectColumns(records[0]); !ok {
+			text := "Не получилось определить колонки выписки. Первая строка файла:%0A"
+			for index, name := range records[0] {
+				text += strconv.Itoa(index+1) + ". " + url.QueryEscape(name) + "%0A"
+			}
+
+			if err = bot.SendMessage(
+				update.Message.Chat.ChatId,
+				text+"%0AНапиши номера колонок с датой, суммой и описанием через пробел, например «1 3 5». "+
+					"Напиши /cancel, если передумал",
+			); err != nil {
+				log.Fatal(err)
+			}
+
+			processing.Create(
+				update.Message.Chat.ChatId,
+				update.Message.From.UserId,
+				models.Command{Name: enums.IMPORT_STATEMENT},
+				models.Extra{Records: records},
+			)
+		} else if operations, preview, err := utils.GetStatementImport(
+			ctx,
+			&db,
+			update.Message.Chat.ChatId,
+			records,
+			columns,
+		); err != nil {
+			log.Println(err)
+
+			if !utils.IsUserError(err) {
+				err = errors.New(enums.UserErrors[enums.UNEXPECTED_ERROR])
+			}
+
+			if err = bot.SendMessage(update.Message.Chat.ChatId, err.Error()); err != nil {
+				log.Fatal(err)
+			}
+		} else if bankNames, err := utils.GetBankNames(ctx, &db, update.Message.Chat.ChatId); err != nil {
+			bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+		} else {
+			keyboard := append([]string{"Для каждой строки отдельно"}, bankNames...)
+
+			bot.ReplyKeyboard.Create(keyboard)
+
+			if err = bot.SendMessage(
+				update.Message.Chat.ChatId,
+				preview+"%0AВ какую копилку записать операции? Напиши /cancel, если передумал",
+			); err != nil {
+				log.Fatal(err)
+			}
+
+			bot.ReplyKeyboard.Destroy()
+			processing.Create(
+				update.Message.Chat.ChatId,
+				update.Message.From.UserId,
+				models.Command{
+					Name: enums.IMPORT_STATEMENT,
+					Step: 1,
+				},
+				models.Extra{
+					Operations: operations,
+					Keyboard:   keyboard,
+				},
+			)
+		}
+		// --------------------------------------------------------------------------------------------------------
+	} else if update.Message.Text == enums.BotCommands[enums.EXPORT] {
+		// --------------------------------------------------------------------------------- handle /export command
+		periods := []string{
+			enums.PeriodFilters[enums.THIS_MONTH],
+			enums.PeriodFilters[enums.LAST_MONTH],
+			enums.PeriodFilters[enums.ALL_TIME],
+		}
+
+		bot.ReplyKeyboard.Create(periods)
+
+		if err := bot.SendMessage(
+			update.Message.Chat.ChatId,
+			"За какой период выгрузить операции? Выбери его, напиши месяц в формате ММ.ГГГГ "+
+				"или период в формате ДД.ММ.ГГГГ-ДД.ММ.ГГГГ. Напиши /cancel, если передумал",
+		); err != nil {
+			log.Fatal(err)
+		}
+
+		bot.ReplyKeyboard.Destroy()
+		processing.Create(
+			update.Message.Chat.ChatId,
+			update.Message.From.UserId,
+			models.Command{Name: enums.EXPORT},
+			models.Extra{
+				Keyboard: periods,
+			},
+		)
+		// --------------------------------------------------------------------------------------------------------
+	} else if update.Message.Text == enums.BotCommands[enums.SET_LEDGER_ACCOUNTS] {
+		// ------------------------------------------------------------------------ handle /ledger_accounts command
+		if settings, err := utils.GetSettings(ctx, &db, update.Message.Chat.ChatId); err != nil {
+			log.Println(err)
+
+			err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+			if err != nil {
+				log.Fatal(err)
+			}
+		} else {
+			income := settings.Income
+			if income == "" {
+				income = "Income:Прочее"
+			}
+
+			bot.ReplyKeyboard.Create([]string{income})
+
+			if err = bot.SendMessage(
+				update.Message.Chat.ChatId,
+				"На какой счёт записывать доходы при выгрузке в hledger и beancount? "+
+					"Напиши его через двоеточие, например Income:Зарплата. Напиши /cancel, если передумал",
+			); err != nil {
+				log.Fatal(err)
+			}
+
+			bot.ReplyKeyboard.Destroy()
+			processing.Create(
+				update.Message.Chat.ChatId,
+				update.Message.From.UserId,
+				models.Command{Name: enums.SET_LEDGER_ACCOUNTS},
+				models.Extra{
+					Settings: settings,
+				},
+			)
+		}
+		// --------------------------------------------------------------------------------------------------------
+	} else if update.Message.Text == enums.BotCommands[enums.CHART] {
+		// ---------------------------------------------------------------------------------- handle /chart command
+		charts := []string{
+			enums.ChartNames[enums.BALANCE_CHART],
+			enums.ChartNames[enums.EXPENSES_CHART],
+			enums.ChartNames[enums.MONTHLY_CHART],
+		}
+
+		bot.ReplyKeyboard.Create(charts)
+
+		if err := bot.SendMessage(
+			update.Message.Chat.ChatId,
+			"Какой график построить? Напиши /cancel, если передумал",
+		); err != nil {
+			log.Fatal(err)
+		}
+
+		bot.ReplyKeyboard.Destroy()
+		processing.Create(
+			update.Message.Chat.ChatId,
+			update.Message.From.UserId,
+			models.Command{Name: enums.CHART},
+			models.Extra{
+				Keyboard: charts,
+			},
+		)
+		// --------------------------------------------------------------------------------------------------------
+	} else if update.Message.Text == enums.BotCommands[enums.SHARE_BANK] {
+		// ----------------------------------------------------------------------------- handle /share_bank command
+		processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+
+		if bankNames, err := utils.GetBankNames(ctx, &db, update.Message.Chat.ChatId); err != nil {
+			bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+		} else {
+			bot.ReplyKeyboard.Create(bankNames)
+
+			if err = bot.SendMessage(
+				update.Message.Chat.ChatId,
+				"Какой копилкой ты хочешь поделиться? Напиши /cancel, если передумал",
+			); err != nil {
+				log.Fatal(err)
+			}
+
+			bot.ReplyKeyboard.Destroy()
+			processing.Create(
+				update.Message.Chat.ChatId,
+				update.Message.From.UserId,
+				models.Command{Name: enums.SHARE_BANK},
+				models.Extra{Keyboard: bankNames},
+			)
+		}
+		// --------------------------------------------------------------------------------------------------------
+	} else if update.Message.Text == enums.BotCommands[enums.CREATE_DEBT] {
+		// ---------------------------------------------------------------------------- handle /create_debt command
+		directions := []string{
+			enums.DebtDirectionNames[enums.LENT],
+			enums.DebtDirectionNames[enums.BORROWED],
+		}
+
+		bot.ReplyKeyboard.Create(directions)
+
+		if err := bot.SendMessage(
+			update.Message.Chat.ChatId,
+			"Кто кому должен? Напиши /cancel, если передумал",
+		); err != nil {
+			log.Fatal(err)
+		}
+
+		bot.ReplyKeyboard.Destroy()
+		processing.Create(
+			update.Message.Chat.ChatId,
+			update.Message.From.UserId,
+			models.Command{Name: enums.CREATE_DEBT},
+			models.Extra{
+				Keyboard: directions,
+			},
+		)
+		// --------------------------------------------------------------------------------------------------------
+	} else if update.Message.Text == enums.BotCommands[enums.REPAY_DEBT] {
+		// ----------------------------------------------------------------------------- handle /repay_debt command
+		if _, labels, err := utils.GetDebts(ctx, &db, update.Message.Chat.ChatId); err != nil {
+			bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+		} else {
+			bot.ReplyKeyboard.Create(labels)
+
+			if err = bot.SendMessage(
+				update.Message.Chat.ChatId,
+				"Какой долг возвращают? Напиши /cancel, если передумал",
+			); err != nil {
+				log.Fatal(err)
+			}
+
+			bot.ReplyKeyboard.Destroy()
+			processing.Create(
+				update.Message.Chat.ChatId,
+				update.Message.From.UserId,
+				models.Command{Name: enums.REPAY_DEBT},
+				models.Extra{
+					Keyboard: labels,
+				},
+			)
+		}
+		// --------------------------------------------------------------------------------------------------------
+	} else if update.Message.Text == enums.BotCommands[enums.GET_DEBTS] {
+		// ---------------------------------------------------------------------------------- handle /debts command
+		processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+
+		if debts, labels, err := utils.GetDebts(ctx, &db, update.Message.Chat.ChatId); err != nil {
+			bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+		} else {
+			var lent, borrowed int
+
+			text := "Твои долги:%0A%0A"
+
+			for index := range debts {
+				text += labels[index][:strings.Index(labels[index], " ")] + " " +
+					utils.GetDebtDescription(ctx, &db, &debts[index]) + "%0A%0A"
+
+				if debts[index].Direction == enums.DebtDirections[enums.LENT] {
+					lent += debts[index].Amount - debts[index].Repaid
+				} else {
+					borrowed += debts[index].Amount - debts[index].Repaid
+				}
+			}
+
+			text += "Тебе должны: " + strconv.Itoa(lent) + " руб.%0AТы должен: " + strconv.Itoa(borrowed) + " руб."
+
+			if err = bot.SendMessage(update.Message.Chat.ChatId, text); err != nil {
+				log.Fatal(err)
+			}
+		}
+		// --------------------------------------------------------------------------------------------------------
+	} else if update.Message.Text == enums.BotCommands[enums.SPLIT] {
+		// ---------------------------------------------------------------------------------- handle /split command
+		if update.Message.Chat.Type != "group" && update.Message.Chat.Type != "supergroup" {
+			if err := bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.GROUP_ONLY]); err != nil {
+				log.Fatal(err)
+			}
+		} else {
+			if err := bot.SendMessage(
+				update.Message.Chat.ChatId,
+				"Сколько ты заплатил? Напиши /cancel, если передумал",
+			); err != nil {
+				log.Fatal(err)
+			}
+
+			processing.Create(
+				update.Message.Chat.ChatId,
+				update.Message.From.UserId,
+				models.Command{Name: enums.SPLIT},
+				models.Extra{
+					Split: &models.Split{
+						Account: update.Message.Chat.ChatId,
+						Payer: models.Participant{
+							User:     update.Message.From.UserId,
+							Username: update.Message.From.Username,
+							Name:     update.Message.From.Name(),
+						},
+						Author: &update.Message.From,
+					},
+				},
+			)
+		}
+		// --------------------------------------------------------------------------------------------------------
+	} else if update.Message.Text == enums.BotCommands[enums.SETTLE] {
+		// --------------------------------------------------------------------------------- handle /settle command
+		processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+
+		if update.Message.Chat.Type != "group" && update.Message.Chat.Type != "supergroup" {
+			if err := bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.GROUP_ONLY]); err != nil {
+				log.Fatal(err)
+			}
+		} else if balances, err := utils.GetSplitBalances(ctx, &db, update.Message.Chat.ChatId); err != nil {
+			log.Println(err)
+
+			if utils.IsUserError(err) {
+				err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+			} else {
+				err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+			}
+			if err != nil {
+				log.Fatal(err)
+			}
+		} else {
+			text := "Балансы участников:" + utils.GetSplitSummary(balances) + "%0A%0A"
+
+			var participants []models.Participant
+			for _, balance := range balances {
+				participants = append(participants, balance.Participant)
+			}
+
+			labels := utils.GetParticipantLabels(participants)
+
+			// the transfers are proposed even when there are none, so that the buttons of earlier messages stop working
+			settlements := utils.GetSettlements(balances)
+
+			if batch, err := utils.ProposeSettlements(
+				ctx,
+				&db,
+				update.Message.Chat.ChatId,
+				settlements,
+				&update.Message.From,
+			); err != nil {
+				log.Println(err)
+
+				text = enums.UserErrors[enums.UNEXPECTED_ERROR]
+			} else if len(settlements) < 1 {
+				text += "Все в расчёте!"
+			} else {
+				text += "Чтобы рассчитаться:"
+				for _, settlement := range settlements {
+					text += "%0A" + url.QueryEscape(labels[settlement.From.Key()]+" → "+labels[settlement.To.Key()]) + ": " +
+						strconv.Itoa(settlement.Amount) + " руб."
+				}
+
+				bot.InlineKeyboard.Create([]models.InlineKeyboardButton{{
+					Text:         "Мы рассчитались",
+					CallbackData: "settle:" + batch,
+				}})
+			}
+
+			if err = bot.SendMessage(update.Message.Chat.ChatId, text); err != nil {
+				log.Fatal(err)
+			}
+
+			bot.InlineKeyboard.Destroy()
+		}
+		// --------------------------------------------------------------------------------------------------------
+	} else {
+		process := processing.Get(update.Message.Chat.ChatId, update.Message.From.UserId)
+
+		// only the buttons of the bank keyboard that is shown now navigate it, so typed names and comments never do
+		var navigation bool
+		for _, button := range process.Extra.Keyboard {
+			if button == update.Message.Text &&
+				(strings.HasSuffix(button, " ▸") || button == "◂ Назад") {
+				navigation = true
+			}
+		}
+
+		if process.Command.Name == enums.UndefinedBotCommand {
+			// -------------------------------------------------------------------------- handle unexpected message
+			if err := bot.SendMessage(
+				update.Message.Chat.ChatId,
+				"Для работы с ботом используй одну из следующих команд:%0A"+
+					"/create_bank - создать копилку%0A"+
+					"/destroy_bank - удалить копилку%0A"+
+					"/income - увеличить баланс копилки%0A"+
+					"/expense - уменьшить баланс копилки%0A"+
+					"/create_transfer - создать перевод%0A"+
+					"/get_balance - узнать баланс копилки%0A"+
+					"/set_goal - поставить цель копилке%0A"+
+					"/set_limit - установить лимит расходов копилки%0A"+
+					"/get_limits - узнать состояние лимитов%0A"+
+					"/set_overdraft - настроить уход копилки в минус%0A"+
+					"/create_schedule - создать регулярную операцию%0A"+
+					"/schedules - список регулярных операций%0A"+
+					"/pause_schedule - приостановить или возобновить регулярную операцию%0A"+
+					"/edit_schedule - изменить регулярную операцию%0A"+
+					"/destroy_schedule - удалить регулярную операцию%0A"+
+					"/digest - настроить ежедневную или еженедельную сводку%0A"+
+					"/history - история операций%0A"+
+					"/undo - отменить последнюю операцию%0A"+
+					"/report - отчёт о доходах и расходах за период%0A"+
+					"/search - найти операции по комментарию%0A"+
+					"/rename_bank - переименовать копилку%0A"+
+					"/merge_banks - объединить две копилки%0A"+
+					"/move_bank - вложить копилку в другую%0A"+
+					"/export - выгрузить операции в CSV или XLSX%0A"+
+					"/ledger_accounts - счета доходов и расходов для hledger и beancount%0A"+
+					"/chart - графики баланса, расходов, доходов и расходов по месяцам%0A"+
+					"/share_bank - поделиться копилкой с другим пользователем%0A"+
+					"/create_debt - записать долг%0A"+
+					"/repay_debt - записать возврат долга%0A"+
+					"/debts - список долгов%0A"+
+					"/split - разделить общий расход в группе%0A"+
+					"/settle - кто кому сколько должен перевести%0A",
+			); err != nil {
+				log.Fatal(err)
+			}
+			// -----------------------------------------------------------------------------------------------------
+		} else if navigation {
+			// ----------------------------------------------------------------- handle bank keyboard navigation
+			extra := process.Extra
+
+			var err error
+
+			if update.Message.Text == "◂ Назад" {
+				var level *models.Bank
+
+				if extra.Level != "" {
+					level, err = utils.GetBankById(ctx, &db, update.Message.Chat.ChatId, extra.Level)
+				}
+
+				if err == nil && level != nil && level.Parent != "" {
+					if level, err = utils.GetBankById(ctx, &db, update.Message.Chat.ChatId, level.Parent); err == nil {
+						extra.Level = level.Id
+						extra.Keyboard, err = utils.GetBankKeyboard(ctx, &db, update.Message.Chat.ChatId, level)
+					}
+				} else if err == nil {
+					extra.Level = ""
+					extra.Keyboard = extra.Root
+
+					if extra.Keyboard == nil {
+						extra.Keyboard, err = utils.GetBankNames(ctx, &db, update.Message.Chat.ChatId)
+					}
+				}
+			} else {
+				var level *models.Bank
+
+				name := strings.TrimSuffix(update.Message.Text, " ▸")
+				if level, err = utils.GetBank(ctx, &db, update.Message.Chat.ChatId, name); err == nil {
+					if extra.Level == "" {
+						extra.Root = extra.Keyboard
+					}
+
+					extra.Level = level.Id
+					extra.Keyboard, err = utils.GetBankKeyboard(ctx, &db, update.Message.Chat.ChatId, level)
+				}
+			}
+
+			if err != nil {
+				log.Println(err)
+
+				if err.Error() == enums.UserErrors[enums.BANK_NOT_FOUND] {
+					bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+				} else {
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+				}
+			} else {
+				bot.ReplyKeyboard.Create(extra.Keyboard)
+
+				if err = bot.SendMessage(update.Message.Chat.ChatId, "Выбери копилку"); err != nil {
+					log.Fatal(err)
+				}
+
+				bot.ReplyKeyboard.Destroy()
+				processing.Create(update.Message.Chat.ChatId, update.Message.From.UserId, process.Command, extra)
+			}
+			// -------------------------------------------------------------------------------------------------
+		} else if process.Command.Name == enums.CREATE_BANK {
+			// ---------------------------------------------------- handle update in /create_bank command processing
+			if process.Command.Step == 0 {
+				bank, err := utils.GetBank(ctx, &db, update.Message.Chat.ChatId, update.Message.Text)
+				if err != nil && err.Error() != enums.UserErrors[enums.BANK_NOT_FOUND] {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+				} else if bank != nil {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.BANK_NAME_IS_EXIST])
+					if err != nil {
+						log.Fatal(err)
+					}
+				} else if bankNames, err := utils.GetBankNames(ctx, &db, update.Message.Chat.ChatId); err == nil {
+					keyboard := append([]string{"Без родительской копилки"}, bankNames...)
+
+					bot.ReplyKeyboard.Create(keyboard)
+
+					if err = bot.SendMessage(
+						update.Message.Chat.ChatId,
+						"В какую копилку вложить новую?",
+					); err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+					processing.Create(
+						update.Message.Chat.ChatId,
+						update.Message.From.UserId,
+						models.Command{
+							Name: enums.CREATE_BANK,
+							Step: 1,
+						},
+						models.Extra{
+							Bank: &models.Bank{
+								Account: update.Message.Chat.ChatId,
+								Name:    update.Message.Text,
+							},
+							Keyboard: keyboard,
+						},
+					)
+				} else {
+					bank = &models.Bank{
+						Account: update.Message.Chat.ChatId,
+						Name:    update.Message.Text,
+					}
+
+					if err = bank.Create(ctx, &db); err != nil {
+						log.Println(err)
+
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+						if err != nil {
+							log.Fatal(err)
+						}
+					} else {
+						if err = bot.SendMessage(
+							update.Message.Chat.ChatId,
+							"Копилка успешно создана!",
+						); err != nil {
+							log.Fatal(err)
+						}
+					}
+
+					processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+				}
+			} else if process.Command.Step == 1 {
+				if update.Message.Text != "Без родительской копилки" {
+					parent, err := utils.GetBank(ctx, &db, update.Message.Chat.ChatId, update.Message.Text)
+					if err == nil {
+						err = utils.CheckRole(parent, update.Message.Chat.ChatId, enums.OWNER)
+					}
+					if err != nil {
+						log.Println(err)
+
+						if utils.IsUserError(err) {
+							bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+							err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+							if err != nil {
+								log.Fatal(err)
+							}
+
+							bot.ReplyKeyboard.Destroy()
+						} else {
+							err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+							if err != nil {
+								log.Fatal(err)
+							}
+
+							processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+						}
+
+						return
+					}
+
+					process.Extra.Bank.Parent = parent.Id
+				}
+
+				if err := process.Extra.Bank.Create(ctx, &db); err != nil {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+					if err != nil {
+						log.Fatal(err)
+					}
+				} else {
+					if err = bot.SendMessage(
+						update.Message.Chat.ChatId,
+						"Копилка успешно создана!",
+					); err != nil {
+						log.Fatal(err)
+					}
+				}
+
+				processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+			}
+			// -------------------------------------------------------------------------------------------------
+		} else if process.Command.Name == enums.DESTROY_BANK {
+			// ----------------------------------------------- handle update in /destroy_bank command processing
+			if process.Command.Step == 0 {
+				if bank, err := utils.GetBank(ctx, &db, update.Message.Chat.ChatId, update.Message.Text); err != nil {
+					log.Println(err)
+
+					if err.Error() == enums.UserErrors[enums.BANK_NOT_FOUND] {
+						bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+						err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						bot.ReplyKeyboard.Destroy()
+					} else {
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+					}
+				} else if err = utils.CheckRole(bank, update.Message.Chat.ChatId, enums.OWNER); err != nil {
+					log.Println(err)
+
+					bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+				} else if bank.Balance != 0 {
+					var keyboard []string
+					for _, name := range process.Extra.Keyboard {
+						if name != bank.Name {
+							keyboard = append(keyboard, name)
+						}
+					}
+
+					keyboard = append(keyboard, "Удалить без переноса")
+
+					bot.ReplyKeyboard.Create(keyboard)
+
+					if err = bot.SendMessage(
+						update.Message.Chat.ChatId,
+						"Баланс копилки "+bank.Name+" составляет "+strconv.Itoa(bank.Balance)+
+							" руб. В какую копилку перенести остаток?",
+					); err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+					processing.Create(
+						update.Message.Chat.ChatId,
+						update.Message.From.UserId,
+						models.Command{
+							Name: enums.DESTROY_BANK,
+							Step: 1,
+						},
+						models.Extra{
+							Bank:     bank,
+							Keyboard: keyboard,
+						},
+					)
+				} else {
+					err = utils.CloseBank(ctx, &db, bank, nil, &update.Message.From)
+					if err != nil {
+						log.Println(err)
+
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+						if err != nil {
+							log.Fatal(err)
+						}
+					} else {
+						if err = bot.SendMessage(
+							update.Message.Chat.ChatId,
+							"Копилка успешно удалена!",
+						); err != nil {
+							log.Fatal(err)
+						}
+					}
+
+					processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+				}
+			} else if process.Command.Step == 1 {
+				var target *models.Bank
+
+				if update.Message.Text != "Удалить без переноса" {
+					bank, err := utils.GetBank(ctx, &db, update.Message.Chat.ChatId, update.Message.Text)
+					if err == nil {
+						err = utils.CheckRole(bank, update.Message.Chat.ChatId, enums.OWNER)
+					}
+					if err != nil {
+						log.Println(err)
+
+						if utils.IsUserError(err) {
+							bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+							err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+							if err != nil {
+								log.Fatal(err)
+							}
+
+							bot.ReplyKeyboard.Destroy()
+						} else {
+							err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+							if err != nil {
+								log.Fatal(err)
+							}
+
+							processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+						}
+
+						return
+					}
+
+					target = bank
+				}
+
+				if err := utils.CloseBank(ctx, &db, process.Extra.Bank, target, &update.Message.From); err != nil {
+					log.Println(err)
+
+					if utils.IsUserError(err) {
+						bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+						err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						bot.ReplyKeyboard.Destroy()
+					} else {
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+					}
+				} else {
+					text := "Копилка успешно удалена!"
+					if target != nil {
+						text += "%0AБаланс копилки " + target.Name + " составляет " + strconv.Itoa(target.Balance) + " руб."
+					}
+
+					if err = bot.SendMessage(update.Message.Chat.ChatId, text); err != nil {
+						log.Fatal(err)
+					}
+
+					processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+				}
+			}
+			// -------------------------------------------------------------------------------------------------
+		} else if process.Command.Name == enums.GET_BALANCE {
+			// ------------------------------------------------ handle update in /get_balance command processing
+			if bank, err := utils.GetBank(ctx, &db, update.Message.Chat.ChatId, update.Message.Text); err != nil {
+				log.Println(err)
+
+				if err.Error() == enums.UserErrors[enums.BANK_NOT_FOUND] {
+					bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+				} else {
+					err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+				}
+			} else if text, err := utils.GetBalance(ctx, &db, bank); err != nil {
+				log.Println(err)
+
+				err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+				if err != nil {
+					log.Fatal(err)
+				}
+
+				processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+			} else {
+				if progress := utils.GetGoalProgress(bank); progress != "" {
+					text += "%0A%0A" + progress
+				}
+
+				if err = bot.SendMessage(update.Message.Chat.ChatId, text); err != nil {
+					log.Fatal(err)
+				}
+
+				processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+			}
+			// -------------------------------------------------------------------------------------------------
+		} else if process.Command.Name == enums.INCOME {
+			// ----------------------------------------------------- handle update in /income command processing
+			if process.Command.Step == 0 {
+				if bank, err := utils.GetBank(ctx, &db, update.Message.Chat.ChatId, update.Message.Text); err != nil {
+					log.Println(err)
+
+					if err.Error() == enums.UserErrors[enums.BANK_NOT_FOUND] {
+						bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+						err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						bot.ReplyKeyboard.Destroy()
+					} else {
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+					}
+				} else if err = utils.CheckRole(bank, update.Message.Chat.ChatId, enums.REQUESTER); err != nil {
+					log.Println(err)
+
+					bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+				} else {
+					processing.Create(
+						update.Message.Chat.ChatId,
+						update.Message.From.UserId,
+						models.Command{
+							Name: enums.INCOME,
+							Step: 1,
+						},
+						models.Extra{
+							Bank: bank,
+							Operation: models.Operation{
+								Account:   bank.Account,
+								Bank:      bank.Id,
+								Operation: enums.BotCommands[enums.INCOME],
+								Amount:    process.Extra.Operation.Amount,
+								Comment:   process.Extra.Operation.Comment,
+							},
+						},
+					)
+
+					// parts of a quick entry are passed to the next steps as if they were sent one by one
+					if process.Extra.Operation.Amount > 0 {
+						update.Message.Text = strconv.Itoa(process.Extra.Operation.Amount)
+						handler(update)
+					} else if err = bot.SendMessage(update.Message.Chat.ChatId, "На какую сумму?"); err != nil {
+						log.Fatal(err)
+					}
+				}
+			} else if process.Command.Step == 1 {
+				amount, expression, err := utils.ParseAmount(update.Message.Text)
+				if update.Message.Text == "Да" && process.Extra.Amount > 0 {
+					amount, expression, err = process.Extra.Amount, false, nil
+				}
+
+				if err != nil {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+					if err != nil {
+						log.Fatal(err)
+					}
+				} else if expression {
+					process.Extra.Amount = amount
+
+					bot.ReplyKeyboard.Create([]string{"Да"})
+
+					if err = bot.SendMessage(
+						update.Message.Chat.ChatId,
+						url.QueryEscape(strings.Join(strings.Fields(update.Message.Text), ""))+" = "+strconv.Itoa(amount)+
+							" руб. Всё верно? Нажми «Да» или введи сумму заново",
+					); err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+					processing.Create(update.Message.Chat.ChatId, update.Message.From.UserId, process.Command, process.Extra)
+				} else {
+					processing.Create(
+						update.Message.Chat.ChatId,
+						update.Message.From.UserId,
+						models.Command{
+							Name: enums.INCOME,
+							Step: 2,
+						},
+						models.Extra{
+							Bank: process.Extra.Bank,
+							Operation: models.Operation{
+								Account:   process.Extra.Operation.Account,
+								Bank:      process.Extra.Operation.Bank,
+								Operation: process.Extra.Operation.Operation,
+								Amount:    amount,
+							},
+						},
+					)
+
+					if process.Extra.Operation.Comment != "" {
+						update.Message.Text = process.Extra.Operation.Comment
+						handler(update)
+					} else if err = bot.SendMessage(
+						update.Message.Chat.ChatId,
+						"Добавь комментарий к операции",
+					); err != nil {
+						log.Fatal(err)
+					}
+				}
+			} else if process.Command.Step == 2 &&
+				utils.GetRole(process.Extra.Bank, update.Message.Chat.ChatId) == enums.REQUESTER {
+				// a requester can't change the balance, so the operation waits until an admin approves it
+				process.Extra.Operation.Comment = update.Message.Text
+				process.Extra.Operation.Author = &update.Message.From
+				process.Extra.Operation.Pending = true
+
+				if err := process.Extra.Operation.Create(ctx, &db); err != nil {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+					if err != nil {
+						log.Fatal(err)
+					}
+				} else {
+					if err = bot.SendMessage(
+						update.Message.Chat.ChatId,
+						"Запрос на доход "+strconv.Itoa(process.Extra.Operation.Amount)+" руб. отправлен администраторам копилки "+
+							process.Extra.Bank.Name+". Баланс изменится, когда запрос одобрят",
+					); err != nil {
+						log.Fatal(err)
+					}
+
+					requestApproval(process.Extra.Bank, &process.Extra.Operation)
+				}
+
+				processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+			} else if process.Command.Step == 2 {
+				process.Extra.Operation.Comment = update.Message.Text
+				process.Extra.Operation.Author = &update.Message.From
+
+				err := process.Extra.Operation.Create(ctx, &db)
+				if err != nil {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+					if err != nil {
+						log.Fatal(err)
+					}
+				} else {
+					// the balance is changed in place, as another member may have changed the bank since it was chosen
+					err = process.Extra.Bank.Increase(ctx, &db, process.Extra.Operation.Amount)
+					if err != nil {
+						log.Println(err)
+
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+						if err != nil {
+							log.Fatal(err)
+						}
+					}
+
+					previous := process.Extra.Bank.Balance - process.Extra.Operation.Amount
+
+					text := "Баланс копилки был успешно изменен! Текущий баланс: " +
+						strconv.Itoa(process.Extra.Bank.Balance) + " руб."
+					if progress := utils.GetGoalProgress(process.Extra.Bank); progress != "" {
+						text += "%0A%0A" + progress
+					}
+
+					bot.InlineKeyboard.Create([]models.InlineKeyboardButton{{
+						Text:         "Отменить",
+						CallbackData: "undo:" + process.Extra.Operation.Id,
+					}})
+
+					if err = bot.SendMessage(update.Message.Chat.ChatId, text); err != nil {
+						log.Fatal(err)
+					}
+
+					bot.InlineKeyboard.Destroy()
+
+					notifyMembers(
+						process.Extra.Bank,
+						update.Message.Chat.ChatId,
+						url.QueryEscape(update.Message.From.Name())+" · копилка "+process.Extra.Bank.Name+": доход "+
+							strconv.Itoa(process.Extra.Operation.Amount)+" руб. Текущий баланс: "+
+							strconv.Itoa(process.Extra.Bank.Balance)+" руб.",
+					)
+
+					if process.Extra.Bank.Goal > 0 && previous < process.Extra.Bank.Goal &&
+						process.Extra.Bank.Balance >= process.Extra.Bank.Goal {
+						if err = bot.SendMessage(
+							update.Message.Chat.ChatId,
+							"Поздравляю! Цель копилки "+process.Extra.Bank.Name+" достигнута 🎉",
+						); err != nil {
+							log.Fatal(err)
+						}
+					}
+				}
+
+				processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+			}
+			// -------------------------------------------------------------------------------------------------
+		} else if process.Command.Name == enums.EXPENSE {
+			// ---------------------------------------------------- handle update in /expense command processing
+			if process.Command.Step == 0 {
+				if bank, err := utils.GetBank(ctx, &db, update.Message.Chat.ChatId, update.Message.Text); err != nil {
+					log.Println(err)
+
+					if err.Error() == enums.UserErrors[enums.BANK_NOT_FOUND] {
+						bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+						err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						bot.ReplyKeyboard.Destroy()
+					} else {
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+					}
+				} else if err = utils.CheckRole(bank, update.Message.Chat.ChatId, enums.REQUESTER); err != nil {
+					log.Println(err)
+
+					bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+				} else {
+					processing.Create(
+						update.Message.Chat.ChatId,
+						update.Message.From.UserId,
+						models.Command{
+							Name: enums.EXPENSE,
+							Step: 1,
+						},
+						models.Extra{
+							Bank: bank,
+							Operation: models.Operation{
+								Account:   bank.Account,
+								Bank:      bank.Id,
+								Operation: enums.BotCommands[enums.EXPENSE],
+								Amount:    process.Extra.Operation.Amount,
+								Comment:   process.Extra.Operation.Comment,
+								Receipt:   process.Extra.Operation.Receipt,
+								CreatedAt: process.Extra.Operation.CreatedAt,
+							},
+						},
+					)
+
+					if process.Extra.Operation.Amount > 0 {
+						update.Message.Text = strconv.Itoa(process.Extra.Operation.Amount)
+						handler(update)
+					} else if err = bot.SendMessage(update.Message.Chat.ChatId, "На какую сумму?"); err != nil {
+						log.Fatal(err)
+					}
+				}
+			} else if process.Command.Step == 1 {
+				amount, expression, err := utils.ParseAmount(update.Message.Text)
+				if update.Message.Text == "Да" && process.Extra.Amount > 0 {
+					amount, expression, err = process.Extra.Amount, false, nil
+				}
+
+				if err != nil {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+					if err != nil {
+						log.Fatal(err)
+					}
+				} else if expression {
+					process.Extra.Amount = amount
+
+					bot.ReplyKeyboard.Create([]string{"Да"})
+
+					if err = bot.SendMessage(
+						update.Message.Chat.ChatId,
+						url.QueryEscape(strings.Join(strings.Fields(update.Message.Text), ""))+" = "+strconv.Itoa(amount)+
+							" руб. Всё верно? Нажми «Да» или введи сумму заново",
+					); err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+					processing.Create(update.Message.Chat.ChatId, update.Message.From.UserId, process.Command, process.Extra)
+				} else {
+					var backup *models.Bank
+					var shortfall int
+
+					// a request changes nothing until it is approved, so the overdraft is checked on approval
+					if utils.GetRole(process.Extra.Bank, update.Message.Chat.ChatId) != enums.REQUESTER {
+						if backup, shortfall, err = utils.GetOverdraftCover(ctx, &db, process.Extra.Bank, amount); err != nil {
+							log.Println(err)
+						}
+					}
+
+					if shortfall > 0 && backup == nil &&
+						process.Extra.Bank.Overdraft == enums.OverdraftPolicies[enums.FORBID] {
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.INSUFFICIENT_FUNDS])
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						return
+					}
+
+					if backup != nil {
+						if err = bot.SendMessage(
+							update.Message.Chat.ChatId,
+							"В копилке "+process.Extra.Bank.Name+" не хватает "+strconv.Itoa(shortfall)+
+								" руб. Недостающая сумма будет переведена из копилки "+backup.Name,
+						); err != nil {
+							log.Fatal(err)
+						}
+					} else if shortfall > 0 && process.Extra.Bank.Overdraft == enums.OverdraftPolicies[enums.WARN] {
+						if err = bot.SendMessage(
+							update.Message.Chat.ChatId,
+							"Внимание! После этой операции баланс копилки "+process.Extra.Bank.Name+
+								" станет отрицательным: -"+strconv.Itoa(shortfall)+" руб.",
+						); err != nil {
+							log.Fatal(err)
+						}
+					}
+
+					if process.Extra.Bank.Limit > 0 {
+						from := utils.GetPeriodStart(process.Extra.Bank.Period, time.Now())
+
+						if spent, err := utils.GetSpent(ctx, &db, process.Extra.Bank, from); err != nil {
+							log.Println(err)
+						} else if spent+amount > process.Extra.Bank.Limit {
+							if err = bot.SendMessage(
+								update.Message.Chat.ChatId,
+								"Внимание! Эта операция превысит лимит копилки "+process.Extra.Bank.Name+
+									" на "+strconv.Itoa(spent+amount-process.Extra.Bank.Limit)+" руб.%0A%0A"+
+									utils.GetLimitProgress(process.Extra.Bank, spent+amount),
+							); err != nil {
+								log.Fatal(err)
+							}
+						} else if (spent+amount)*100 >= process.Extra.Bank.Limit*90 {
+							if err = bot.SendMessage(
+								update.Message.Chat.ChatId,
+								"Осторожно, лимит копилки "+process.Extra.Bank.Name+" почти исчерпан!%0A%0A"+
+									utils.GetLimitProgress(process.Extra.Bank, spent+amount),
+							); err != nil {
+								log.Fatal(err)
+							}
+						}
+					}
+
+					processing.Create(
+						update.Message.Chat.ChatId,
+						update.Message.From.UserId,
+						models.Command{
+							Name: enums.EXPENSE,
+							Step: 2,
+						},
+						models.Extra{
+							Bank: process.Extra.Bank,
+							Operation: models.Operation{
+								Account:   process.Extra.Operation.Account,
+								Bank:      process.Extra.Operation.Bank,
+								Operation: process.Extra.Operation.Operation,
+								Amount:    amount,
+								Receipt:   process.Extra.Operation.Receipt,
+								CreatedAt: process.Extra.Operation.CreatedAt,
+							},
+						},
+					)
+
+					if process.Extra.Operation.Comment != "" {
+						update.Message.Text = process.Extra.Operation.Comment
+						handler(update)
+					} else if err = bot.SendMessage(
+						update.Message.Chat.ChatId,
+						"Добавь комментарий к операции",
+					); err != nil {
+						log.Fatal(err)
+					}
+				}
+			} else if process.Command.Step == 2 &&
+				utils.GetRole(process.Extra.Bank, update.Message.Chat.ChatId) == enums.REQUESTER {
+				// a requester can't change the balance, so the operation waits until an admin approves it
+				process.Extra.Operation.Comment = update.Message.Text
+				process.Extra.Operation.Author = &update.Message.From
+				process.Extra.Operation.Pending = true
+
+				if err := process.Extra.Operation.Create(ctx, &db); err != nil {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+					if err != nil {
+						log.Fatal(err)
+					}
+				} else {
+					if err = bot.SendMessage(
+						update.Message.Chat.ChatId,
+						"Запрос на расход "+strconv.Itoa(process.Extra.Operation.Amount)+" руб. отправлен администраторам копилки "+
+							process.Extra.Bank.Name+". Баланс изменится, когда запрос одобрят",
+					); err != nil {
+						log.Fatal(err)
+					}
+
+					requestApproval(process.Extra.Bank, &process.Extra.Operation)
+				}
+
+				processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+			} else if process.Command.Step == 2 {
+				var text string
+
+				// the bank may have been changed by another member since it was chosen, so the shortfall is computed anew
+				bank, err := utils.GetBankById(ctx, &db, process.Extra.Bank.Account, process.Extra.Bank.Id)
+				if err != nil {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+
+					return
+				}
+
+				process.Extra.Bank = bank
+
+				backup, shortfall, err := utils.GetOverdraftCover(ctx, &db, process.Extra.Bank, process.Extra.Operation.Amount)
+				if err == nil && shortfall > 0 && backup == nil &&
+					process.Extra.Bank.Overdraft == enums.OverdraftPolicies[enums.FORBID] {
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.INSUFFICIENT_FUNDS])
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+
+					return
+				}
+
+				if err != nil {
+					log.Println(err)
+				} else if backup != nil {
+					if cover, err := utils.CreateTransfer(ctx, &db, backup, process.Extra.Bank, shortfall, &update.Message.From); err != nil {
+						log.Println(err)
+					} else {
+						process.Extra.Operation.Cover = cover.Transfer
+						text += "Недостающие " + strconv.Itoa(shortfall) + " руб. были переведены из копилки " + backup.Name + "%0A%0A"
+					}
+				}
+
+				process.Extra.Operation.Comment = update.Message.Text
+				process.Extra.Operation.Author = &update.Message.From
+
+				err = process.Extra.Operation.Create(ctx, &db)
+				if err != nil {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+					if err != nil {
+						log.Fatal(err)
+					}
+				} else {
+					err := process.Extra.Bank.Increase(ctx, &db, -process.Extra.Operation.Amount)
+					if err != nil {
+						log.Println(err)
+
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+						if err != nil {
+							log.Fatal(err)
+						}
+					}
+
+					bot.InlineKeyboard.Create([]models.InlineKeyboardButton{{
+						Text:         "Отменить",
+						CallbackData: "undo:" + process.Extra.Operation.Id,
+					}})
+
+					if err = bot.SendMessage(
+						update.Message.Chat.ChatId,
+						text+"Баланс копилки был успешно изменен! Текущий баланс: "+strconv.Itoa(process.Extra.Bank.Balance)+" руб.",
+					); err != nil {
+						log.Fatal(err)
+					}
+
+					bot.InlineKeyboard.Destroy()
+
+					notifyMembers(
+						process.Extra.Bank,
+						update.Message.Chat.ChatId,
+						url.QueryEscape(update.Message.From.Name())+" · копилка "+process.Extra.Bank.Name+": расход "+
+							strconv.Itoa(process.Extra.Operation.Amount)+" руб. Текущий баланс: "+strconv.Itoa(process.Extra.Bank.Balance)+" руб.",
+					)
+				}
+
+				processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+			}
+			// -------------------------------------------------------------------------------------------------
+		} else if process.Command.Name == enums.CREATE_TRANSFER {
+			// ----------------------------------------------- handle update in /create_transfer command handler
+			if process.Command.Step == 0 {
+				if bank, err := utils.GetBank(ctx, &db, update.Message.Chat.ChatId, update.Message.Text); err != nil {
+					log.Println(err)
+
+					if err.Error() == enums.UserErrors[enums.BANK_NOT_FOUND] {
+						bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+						err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						bot.ReplyKeyboard.Destroy()
+					} else {
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+					}
+				} else if err = utils.CheckRole(bank, update.Message.Chat.ChatId, enums.CONTRIBUTOR); err != nil {
+					log.Println(err)
+
+					bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+				} else {
+					err = bot.SendMessage(update.Message.Chat.ChatId, "Какую сумму?")
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					processing.Create(
+						update.Message.Chat.ChatId,
+						update.Message.From.UserId,
+						models.Command{
+							Name: enums.CREATE_TRANSFER,
+							Step: 1,
+						},
+						models.Extra{
+							Bank: bank,
+							Operation: models.Operation{
+								Account:   bank.Account,
+								Bank:      bank.Id,
+								Operation: enums.BotCommands[enums.EXPENSE],
+							},
+							Keyboard: process.Extra.Keyboard,
+						},
+					)
+				}
+			} else if process.Command.Step == 1 {
+				amount, expression, err := utils.ParseAmount(update.Message.Text)
+				if update.Message.Text == "Да" && process.Extra.Amount > 0 {
+					amount, expression, err = process.Extra.Amount, false, nil
+				}
+
+				if err != nil {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+					if err != nil {
+						log.Fatal(err)
+					}
+				} else if expression {
+					process.Extra.Amount = amount
+
+					bot.ReplyKeyboard.Create([]string{"Да"})
+
+					if err = bot.SendMessage(
+						update.Message.Chat.ChatId,
+						url.QueryEscape(strings.Join(strings.Fields(update.Message.Text), ""))+" = "+strconv.Itoa(amount)+
+							" руб. Всё верно? Нажми «Да» или введи сумму заново",
+					); err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+					processing.Create(update.Message.Chat.ChatId, update.Message.From.UserId, process.Command, process.Extra)
+				} else {
+					backup, shortfall, err := utils.GetOverdraftCover(ctx, &db, process.Extra.Bank, amount)
+					if err != nil {
+						log.Println(err)
+					}
+
+					if shortfall > 0 && backup == nil &&
+						process.Extra.Bank.Overdraft == enums.OverdraftPolicies[enums.FORBID] {
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.INSUFFICIENT_FUNDS])
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						return
+					}
+
+					if backup != nil {
+						if err = bot.SendMessage(
+							update.Message.Chat.ChatId,
+							"В копилке "+process.Extra.Bank.Name+" не хватает "+strconv.Itoa(shortfall)+
+								" руб. Недостающая сумма будет переведена из копилки "+backup.Name,
+						); err != nil {
+							log.Fatal(err)
+						}
+					} else if shortfall > 0 && process.Extra.Bank.Overdraft == enums.OverdraftPolicies[enums.WARN] {
+						if err = bot.SendMessage(
+							update.Message.Chat.ChatId,
+							"Внимание! После перевода баланс копилки "+process.Extra.Bank.Name+
+								" станет отрицательным: -"+strconv.Itoa(shortfall)+" руб.",
+						); err != nil {
+							log.Fatal(err)
+						}
+					}
+
+					bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+					if err = bot.SendMessage(
+						update.Message.Chat.ChatId,
+						"В какую копилку?",
+					); err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+					processing.Create(
+						update.Message.Chat.ChatId,
+						update.Message.From.UserId,
+						models.Command{
+							Name: enums.CREATE_TRANSFER,
+							Step: 2,
+						},
+						models.Extra{
+							Bank: process.Extra.Bank,
+							Operation: models.Operation{
+								Account:   process.Extra.Operation.Account,
+								Bank:      process.Extra.Operation.Bank,
+								Operation: process.Extra.Operation.Operation,
+								Amount:    amount,
+							},
+							Keyboard: process.Extra.Keyboard,
+						},
+					)
+				}
+			} else if process.Command.Step == 2 {
+				bankForIncome, err := utils.GetBank(ctx, &db, update.Message.Chat.ChatId, update.Message.Text)
+				if err == nil {
+					err = utils.CheckRole(bankForIncome, update.Message.Chat.ChatId, enums.CONTRIBUTOR)
+				}
+				if err != nil {
+					log.Println(err)
+
+					if utils.IsUserError(err) {
+						bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+						err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						bot.ReplyKeyboard.Destroy()
+					} else {
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+					}
+
+					return
+				}
+
+				var text string
+
+				// the bank may have been changed by another member since it was chosen, so the shortfall is computed anew
+				bank, err := utils.GetBankById(ctx, &db, process.Extra.Bank.Account, process.Extra.Bank.Id)
+				if err != nil {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+
+					return
+				}
+
+				process.Extra.Bank = bank
+
+				backup, shortfall, err := utils.GetOverdraftCover(ctx, &db, process.Extra.Bank, process.Extra.Operation.Amount)
+				if err == nil && shortfall > 0 && backup == nil &&
+					process.Extra.Bank.Overdraft == enums.OverdraftPolicies[enums.FORBID] {
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.INSUFFICIENT_FUNDS])
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+
+					return
+				}
+
+				if err != nil {
+					log.Println(err)
+				} else if backup != nil && backup.Id != bankForIncome.Id {
+					if cover, err := utils.CreateTransfer(ctx, &db, backup, process.Extra.Bank, shortfall, &update.Message.From); err != nil {
+						log.Println(err)
+					} else {
+						process.Extra.Operation.Cover = cover.Transfer
+						text += "Недостающие " + strconv.Itoa(shortfall) + " руб. были переведены из копилки " + backup.Name + "%0A%0A"
+					}
+				}
+
+				previous := bankForIncome.Balance
+
+				transfer, err := utils.CreateTransfer(
+					ctx,
+					&db,
+					process.Extra.Bank,
+					bankForIncome,
+					process.Extra.Operation.Amount,
+					&update.Message.From,
+				)
+				if err != nil {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+					if err != nil {
+						log.Fatal(err)
+					}
+				} else {
+					// the cover is linked to the transfer, so undoing the transfer undoes the cover too
+					if process.Extra.Operation.Cover != "" {
+						if err = transfer.Update(ctx, &db, bson.M{"cover": process.Extra.Operation.Cover}); err != nil {
+							log.Println(err)
+						}
+					}
+
+					bot.InlineKeyboard.Create([]models.InlineKeyboardButton{{
+						Text:         "Отменить",
+						CallbackData: "undo:" + transfer.Id,
+					}})
+
+					if err = bot.SendMessage(
+						update.Message.Chat.ChatId,
+						text+"Из копилки "+process.Extra.Bank.Name+" в копилку "+bankForIncome.Name+
+							" было успешно переведено "+strconv.Itoa(process.Extra.Operation.Amount)+" руб.%0A%0A"+
+							"Баланс копилки "+process.Extra.Bank.Name+
+							" составляет "+strconv.Itoa(process.Extra.Bank.Balance)+" руб.%0A"+
+							"Баланс копилки "+bankForIncome.Name+
+							" составляет "+strconv.Itoa(bankForIncome.Balance)+" руб.%0A",
+					); err != nil {
+						log.Fatal(err)
+					}
+
+					bot.InlineKeyboard.Destroy()
+
+					for _, bank := range []*models.Bank{process.Extra.Bank, bankForIncome} {
+						notifyMembers(
+							bank,
+							update.Message.Chat.ChatId,
+							url.QueryEscape(update.Message.From.Name())+" · перевод "+strconv.Itoa(process.Extra.Operation.Amount)+" руб. из копилки "+
+								process.Extra.Bank.Name+" в копилку "+bankForIncome.Name+". Баланс копилки "+bank.Name+
+								" составляет "+strconv.Itoa(bank.Balance)+" руб.",
+						)
+					}
+
+					if bankForIncome.Goal > 0 && previous < bankForIncome.Goal &&
+						bankForIncome.Balance >= bankForIncome.Goal {
+						if err = bot.SendMessage(
+							update.Message.Chat.ChatId,
+							"Поздравляю! Цель копилки "+bankForIncome.Name+" достигнута 🎉",
+						); err != nil {
+							log.Fatal(err)
+						}
+					}
+				}
+
+				processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+			}
+			// -------------------------------------------------------------------------------------------------
+		} else if process.Command.Name == enums.SET_GOAL {
+			// --------------------------------------------------- handle update in /set_goal command processing
+			if process.Command.Step == 0 {
+				if bank, err := utils.GetBank(ctx, &db, update.Message.Chat.ChatId, update.Message.Text); err != nil {
+					log.Println(err)
+
+					if err.Error() == enums.UserErrors[enums.BANK_NOT_FOUND] {
+						bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+						err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						bot.ReplyKeyboard.Destroy()
+					} else {
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+					}
+				} else if err = utils.CheckRole(bank, update.Message.Chat.ChatId, enums.ADMIN); err != nil {
+					log.Println(err)
+
+					bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+				} else {
+					if err = bot.SendMessage(
+						update.Message.Chat.ChatId,
+						"Какую сумму нужно накопить? Напиши 0, чтобы убрать цель",
+					); err != nil {
+						log.Fatal(err)
+					}
+
+					processing.Create(
+						update.Message.Chat.ChatId,
+						update.Message.From.UserId,
+						models.Command{
+							Name: enums.SET_GOAL,
+							Step: 1,
+						},
+						models.Extra{
+							Bank: bank,
+						},
+					)
+				}
+			} else if process.Command.Step == 1 {
+				goal, err := strconv.Atoi(update.Message.Text)
+				if err != nil || goal < 0 {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.INCORRECT_VALUE])
+					if err != nil {
+						log.Fatal(err)
+					}
+				} else if goal == 0 {
+					if err = process.Extra.Bank.Update(ctx, &db, bson.M{
+						"goal":     0,
+						"deadline": time.Time{},
+					}); err != nil {
+						log.Println(err)
+
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+						if err != nil {
+							log.Fatal(err)
+						}
+					} else {
+						if err = bot.SendMessage(
+							update.Message.Chat.ChatId,
+							"Цель копилки "+process.Extra.Bank.Name+" удалена",
+						); err != nil {
+							log.Fatal(err)
+						}
+
+						notifyMembers(
+							process.Extra.Bank,
+							update.Message.Chat.ChatId,
+							url.QueryEscape(update.Message.From.Name())+" · цель копилки "+process.Extra.Bank.Name+" удалена",
+						)
+					}
+
+					processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+				} else {
+					bot.ReplyKeyboard.Create([]string{"Без срока"})
+
+					if err = bot.SendMessage(
+						update.Message.Chat.ChatId,
+						"К какой дате? Напиши дату в формате ДД.ММ.ГГГГ",
+					); err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+					processing.Create(
+						update.Message.Chat.ChatId,
+						update.Message.From.UserId,
+						models.Command{
+							Name: enums.SET_GOAL,
+							Step: 2,
+						},
+						models.Extra{
+							Bank: process.Extra.Bank,
+							Goal: goal,
+						},
+					)
+				}
+			} else if process.Command.Step == 2 {
+				var deadline time.Time
+				var err error
+
+				if update.Message.Text != "Без срока" {
+					deadline, err = time.ParseInLocation("02.01.2006", update.Message.Text, time.Local)
+					if err != nil || deadline.Before(utils.GetPeriodStart(enums.Periods[enums.DAY], time.Now())) {
+						log.Println(err)
+
+						bot.ReplyKeyboard.Create([]string{"Без срока"})
+
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.INCORRECT_DATE])
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						bot.ReplyKeyboard.Destroy()
+
+						return
+					}
+				}
+
+				if err = process.Extra.Bank.Update(ctx, &db, bson.M{
+					"goal":     process.Extra.Goal,
+					"deadline": deadline,
+				}); err != nil {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+					if err != nil {
+						log.Fatal(err)
+					}
+				} else {
+					if err = bot.SendMessage(
+						update.Message.Chat.ChatId,
+						"Цель для копилки "+process.Extra.Bank.Name+" установлена!%0A%0A"+
+							utils.GetGoalProgress(process.Extra.Bank),
+					); err != nil {
+						log.Fatal(err)
+					}
+
+					notifyMembers(
+						process.Extra.Bank,
+						update.Message.Chat.ChatId,
+						url.QueryEscape(update.Message.From.Name())+" · цель для копилки "+process.Extra.Bank.Name+" установлена%0A%0A"+
+							utils.GetGoalProgress(process.Extra.Bank),
+					)
+				}
+
+				processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+			}
+			// -------------------------------------------------------------------------------------------------
+		} else if process.Command.Name == enums.SET_LIMIT {
+			// -------------------------------------------------- handle update in /set_limit command processing
+			if process.Command.Step == 0 {
+				if bank, err := utils.GetBank(ctx, &db, update.Message.Chat.ChatId, update.Message.Text); err != nil {
+					log.Println(err)
+
+					if err.Error() == enums.UserErrors[enums.BANK_NOT_FOUND] {
+						bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+						err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						bot.ReplyKeyboard.Destroy()
+					} else {
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+					}
+				} else if err = utils.CheckRole(bank, update.Message.Chat.ChatId, enums.ADMIN); err != nil {
+					log.Println(err)
+
+					bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+				} else {
+					periods := []string{enums.PeriodNames[enums.WEEK], enums.PeriodNames[enums.MONTH]}
+
+					bot.ReplyKeyboard.Create(periods)
+
+					if err = bot.SendMessage(update.Message.Chat.ChatId, "На какой период?"); err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+					processing.Create(
+						update.Message.Chat.ChatId,
+						update.Message.From.UserId,
+						models.Command{
+							Name: enums.SET_LIMIT,
+							Step: 1,
+						},
+						models.Extra{
+							Bank:     bank,
+							Keyboard: periods,
+						},
+					)
+				}
+			} else if process.Command.Step == 1 {
+				var period string
+
+				for key, name := range enums.PeriodNames {
+					if key != enums.UndefinedPeriod && name == update.Message.Text {
+						period = enums.Periods[key]
+					}
+				}
+
+				if period == "" {
+					bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+					err := bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.INCORRECT_VALUE])
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+				} else {
+					if err := bot.SendMessage(
+						update.Message.Chat.ChatId,
+						"Сколько можно тратить за этот период? Напиши 0, чтобы убрать лимит",
+					); err != nil {
+						log.Fatal(err)
+					}
+
+					process.Extra.Bank.Period = period
+					processing.Create(
+						update.Message.Chat.ChatId,
+						update.Message.From.UserId,
+						models.Command{
+							Name: enums.SET_LIMIT,
+							Step: 2,
+						},
+						models.Extra{
+							Bank: process.Extra.Bank,
+						},
+					)
+				}
+			} else if process.Command.Step == 2 {
+				limit, err := strconv.Atoi(update.Message.Text)
+				if err != nil || limit < 0 {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.INCORRECT_VALUE])
+					if err != nil {
+						log.Fatal(err)
+					}
+				} else {
+					if err = process.Extra.Bank.Update(ctx, &db, bson.M{
+						"limit":  limit,
+						"period": process.Extra.Bank.Period,
+					}); err != nil {
+						log.Println(err)
+
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+						if err != nil {
+							log.Fatal(err)
+						}
+					} else if limit == 0 {
+						if err = bot.SendMessage(
+							update.Message.Chat.ChatId,
+							"Лимит копилки "+process.Extra.Bank.Name+" удалён",
+						); err != nil {
+							log.Fatal(err)
+						}
+
+						notifyMembers(
+							process.Extra.Bank,
+							update.Message.Chat.ChatId,
+							url.QueryEscape(update.Message.From.Name())+" · лимит копилки "+process.Extra.Bank.Name+" удалён",
+						)
+					} else {
+						from := utils.GetPeriodStart(process.Extra.Bank.Period, time.Now())
+
+						spent, err := utils.GetSpent(ctx, &db, process.Extra.Bank, from)
+						if err != nil {
+							log.Println(err)
+						}
+
+						if err = bot.SendMessage(
+							update.Message.Chat.ChatId,
+							"Лимит для копилки "+process.Extra.Bank.Name+" установлен!%0A%0A"+
+								utils.GetLimitProgress(process.Extra.Bank, spent),
+						); err != nil {
+							log.Fatal(err)
+						}
+
+						notifyMembers(
+							process.Extra.Bank,
+							update.Message.Chat.ChatId,
+							url.QueryEscape(update.Message.From.Name())+" · лимит для копилки "+process.Extra.Bank.Name+" установлен%0A%0A"+
+								utils.GetLimitProgress(process.Extra.Bank, spent),
+						)
+					}
+
+					processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+				}
+			}
+			// -------------------------------------------------------------------------------------------------
+		} else if process.Command.Name == enums.SET_OVERDRAFT {
+			// ---------------------------------------------- handle update in /set_overdraft command processing
+			if process.Command.Step == 0 {
+				if bank, err := utils.GetBank(ctx, &db, update.Message.Chat.ChatId, update.Message.Text); err != nil {
+					log.Println(err)
+
+					if err.Error() == enums.UserErrors[enums.BANK_NOT_FOUND] {
+						bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+						err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						bot.ReplyKeyboard.Destroy()
+					} else {
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+					}
+				} else if err = utils.CheckRole(bank, update.Message.Chat.ChatId, enums.OWNER); err != nil {
+					log.Println(err)
+
+					bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+				} else {
+					policies := []string{
+						enums.OverdraftPolicyNames[enums.FORBID],
+						enums.OverdraftPolicyNames[enums.WARN],
+						enums.OverdraftPolicyNames[enums.ALLOW],
+					}
+
+					bot.ReplyKeyboard.Create(policies)
+
+					if err = bot.SendMessage(
+						update.Message.Chat.ChatId,
+						"Что делать, если баланс копилки уходит в минус?",
+					); err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+					processing.Create(
+						update.Message.Chat.ChatId,
+						update.Message.From.UserId,
+						models.Command{
+							Name: enums.SET_OVERDRAFT,
+							Step: 1,
+						},
+						models.Extra{
+							Bank:     bank,
+							Keyboard: policies,
+						},
+					)
+				}
+			} else if process.Command.Step == 1 {
+				var policy string
+
+				for key, name := range enums.OverdraftPolicyNames {
+					if key != enums.UndefinedOverdraftPolicy && name == update.Message.Text {
+						policy = enums.OverdraftPolicies[key]
+					}
+				}
+
+				if policy == "" {
+					bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+					err := bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.INCORRECT_VALUE])
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+				} else if bankNames, err := utils.GetBankNames(ctx, &db, update.Message.Chat.ChatId); err != nil {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+				} else {
+					keyboard := []string{"Без резервной копилки"}
+
+					for _, bankName := range bankNames {
+						if bankName != process.Extra.Bank.Name && bankName != process.Extra.Bank.Name+" ▸" {
+							keyboard = append(keyboard, bankName)
+						}
+					}
+
+					bot.ReplyKeyboard.Create(keyboard)
+
+					if err = bot.SendMessage(
+						update.Message.Chat.ChatId,
+						"Из какой копилки покрывать нехватку средств?",
+					); err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+
+					process.Extra.Bank.Overdraft = policy
+					processing.Create(
+						update.Message.Chat.ChatId,
+						update.Message.From.UserId,
+						models.Command{
+							Name: enums.SET_OVERDRAFT,
+							Step: 2,
+						},
+						models.Extra{
+							Bank:     process.Extra.Bank,
+							Keyboard: keyboard,
+						},
+					)
+				}
+			} else if process.Command.Step == 2 {
+				var backup *models.Bank
+
+				if update.Message.Text != "Без резервной копилки" {
+					bank, err := utils.GetBank(ctx, &db, update.Message.Chat.ChatId, update.Message.Text)
+					if err == nil {
+						err = utils.CheckRole(bank, update.Message.Chat.ChatId, enums.OWNER)
+					}
+					if err != nil || bank.Id == process.Extra.Bank.Id {
+						log.Println(err)
+
+						text := enums.UserErrors[enums.BANK_NOT_FOUND]
+						if err != nil && err.Error() == enums.UserErrors[enums.NO_PERMISSION] {
+							text = err.Error()
+						}
+
+						bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+						err = bot.SendMessage(update.Message.Chat.ChatId, text)
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						bot.ReplyKeyboard.Destroy()
+
+						return
+					}
+
+					backup = bank
+				}
+
+				fields := bson.M{
+					"overdraft": process.Extra.Bank.Overdraft,
+					"backup":    "",
+				}
+				if backup != nil {
+					fields["backup"] = backup.Id
+				}
+
+				if err := process.Extra.Bank.Update(ctx, &db, fields); err != nil {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+					if err != nil {
+						log.Fatal(err)
+					}
+				} else {
+					text := "Настройки копилки " + process.Extra.Bank.Name + " сохранены!"
+					if backup != nil {
+						text += " Нехватка средств будет покрываться из копилки " + backup.Name
+					}
+
+					if err = bot.SendMessage(update.Message.Chat.ChatId, text); err != nil {
+						log.Fatal(err)
+					}
+				}
+
+				processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+			}
+			// -------------------------------------------------------------------------------------------------
+		} else if process.Command.Name == enums.CREATE_SCHEDULE {
+			// -------------------------------------------- handle update in /create_schedule command processing
+			if process.Command.Step == 0 {
+				var operation string
+
+				for key, name := range enums.OperationNames {
+					if name == update.Message.Text {
+						operation = enums.BotCommands[key]
+					}
+				}
+
+				if operation == "" {
+					bot.ReplyKeyboard.Create([]string{
+						enums.OperationNames[enums.INCOME],
+						enums.OperationNames[enums.EXPENSE],
+						enums.OperationNames[enums.CREATE_TRANSFER],
+					})
+
+					err := bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.INCORRECT_VALUE])
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+				} else {
+					text := "С какой копилкой?"
+					if operation == enums.BotCommands[enums.CREATE_TRANSFER] {
+						text = "Из какой копилки будем переводить средства?"
+					}
+
+					bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+					if err := bot.SendMessage(update.Message.Chat.ChatId, text); err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+					processing.Create(
+						update.Message.Chat.ChatId,
+						update.Message.From.UserId,
+						models.Command{
+							Name: enums.CREATE_SCHEDULE,
+							Step: 1,
+						},
+						models.Extra{
+							Schedule: &models.Schedule{
+								Account:   update.Message.Chat.ChatId,
+								Operation: operation,
+							},
+							Keyboard: process.Extra.Keyboard,
+						},
+					)
+				}
+			} else if process.Command.Step == 1 || process.Command.Step == 2 {
+				if bank, err := utils.GetBank(ctx, &db, update.Message.Chat.ChatId, update.Message.Text); err != nil {
+					log.Println(err)
+
+					if err.Error() == enums.UserErrors[enums.BANK_NOT_FOUND] {
+						bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+						err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						bot.ReplyKeyboard.Destroy()
+					} else {
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+					}
+				} else if process.Command.Step == 2 && bank.Id == process.Extra.Schedule.Bank {
+					bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.SAME_BANK])
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+				} else if err = utils.CheckRole(bank, update.Message.Chat.ChatId, enums.OWNER); err != nil {
+					log.Println(err)
+
+					bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+				} else {
+					step := 3
+
+					if process.Command.Step == 1 {
+						process.Extra.Schedule.Bank = bank.Id
+
+						if process.Extra.Schedule.Operation == enums.BotCommands[enums.CREATE_TRANSFER] {
+							step = 2
+						}
+					} else {
+						process.Extra.Schedule.Target = bank.Id
+					}
+
+					if step == 2 {
+						bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+						err = bot.SendMessage(update.Message.Chat.ChatId, "В какую копилку?")
+					} else {
+						err = bot.SendMessage(update.Message.Chat.ChatId, "На какую сумму?")
+					}
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+					processing.Create(
+						update.Message.Chat.ChatId,
+						update.Message.From.UserId,
+						models.Command{
+							Name: enums.CREATE_SCHEDULE,
+							Step: step,
+						},
+						models.Extra{
+							Schedule: process.Extra.Schedule,
+							Keyboard: process.Extra.Keyboard,
+						},
+					)
+				}
+			} else if process.Command.Step == 3 {
+				amount, err := strconv.Atoi(update.Message.Text)
+				if err != nil || amount <= 0 {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.INCORRECT_VALUE])
+					if err != nil {
+						log.Fatal(err)
+					}
+				} else {
+					if err = bot.SendMessage(
+						update.Message.Chat.ChatId,
+						"Добавь комментарий к операции",
+					); err != nil {
+						log.Fatal(err)
+					}
+
+					process.Extra.Schedule.Amount = amount
+					processing.Create(
+						update.Message.Chat.ChatId,
+						update.Message.From.UserId,
+						models.Command{
+							Name: enums.CREATE_SCHEDULE,
+							Step: 4,
+						},
+						models.Extra{
+							Schedule: process.Extra.Schedule,
+						},
+					)
+				}
+			} else if process.Command.Step == 4 {
+				bot.ReplyKeyboard.Create([]string{
+					"Каждый месяц 1 числа",
+					"Каждую пятницу в 18:00",
+					"Каждый день в 09:00",
+				})
+
+				if err := bot.SendMessage(
+					update.Message.Chat.ChatId,
+					"Как часто повторять операцию? Например: «каждый месяц 5 числа в 10:00», "+
+						"«каждый понедельник» или cron-выражение «0 9 5 * *»",
+				); err != nil {
+					log.Fatal(err)
+				}
+
+				bot.ReplyKeyboard.Destroy()
+
+				process.Extra.Schedule.Comment = update.Message.Text
+				processing.Create(
+					update.Message.Chat.ChatId,
+					update.Message.From.UserId,
+					models.Command{
+						Name: enums.CREATE_SCHEDULE,
+						Step: 5,
+					},
+					models.Extra{
+						Schedule: process.Extra.Schedule,
+					},
+				)
+			} else if process.Command.Step == 5 {
+				cron, err := utils.ParseScheduleRule(update.Message.Text)
+				if err != nil {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.INCORRECT_SCHEDULE])
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					return
+				}
+
+				next, err := utils.GetNextRun(cron, time.Now())
+				if err != nil {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.INCORRECT_SCHEDULE])
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					return
+				}
+
+				process.Extra.Schedule.Rule = update.Message.Text
+				process.Extra.Schedule.Cron = cron
+				process.Extra.Schedule.NextRun = next
+
+				if err = process.Extra.Schedule.Create(ctx, &db); err != nil {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+					if err != nil {
+						log.Fatal(err)
+					}
+				} else {
+					if err = bot.SendMessage(
+						update.Message.Chat.ChatId,
+						"Регулярная операция создана!%0A%0A"+
+							utils.GetScheduleDescription(ctx, &db, process.Extra.Schedule),
+					); err != nil {
+						log.Fatal(err)
+					}
+				}
+
+				processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+			}
+			// -------------------------------------------------------------------------------------------------
+		} else if process.Command.Name == enums.PAUSE_SCHEDULE ||
+			process.Command.Name == enums.EDIT_SCHEDULE ||
+			process.Command.Name == enums.DESTROY_SCHEDULE {
+			// ------- handle update in /pause_schedule, /edit_schedule and /destroy_schedule command processing
+			if process.Command.Step == 0 {
+				schedules, labels, err := utils.GetSchedules(ctx, &db, update.Message.Chat.ChatId)
+				if err != nil {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+
+					return
+				}
+
+				var schedule *models.Schedule
+
+				for index := range labels {
+					if labels[index] == update.Message.Text {
+						schedule = &schedules[index]
+					}
+				}
+
+				if schedule == nil {
+					bot.ReplyKeyboard.Create(labels)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.SCHEDULE_NOT_FOUND])
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+				} else if process.Command.Name == enums.PAUSE_SCHEDULE {
+					fields := bson.M{"paused": !schedule.Paused}
+
+					if schedule.Paused {
+						next, err := utils.GetNextRun(schedule.Cron, time.Now())
+						if err != nil {
+							log.Println(err)
+
+							err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+							if err != nil {
+								log.Fatal(err)
+							}
+
+							processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+
+							return
+						}
+
+						fields["next_run"] = next
+					}
+
+					if err = schedule.Update(ctx, &db, fields); err != nil {
+						log.Println(err)
+
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+						if err != nil {
+							log.Fatal(err)
+						}
+					} else {
+						text := "Регулярная операция возобновлена!"
+						if schedule.Paused {
+							text = "Регулярная операция приостановлена!"
+						}
+
+						if err = bot.SendMessage(
+							update.Message.Chat.ChatId,
+							text+"%0A%0A"+utils.GetScheduleDescription(ctx, &db, schedule),
+						); err != nil {
+							log.Fatal(err)
+						}
+					}
+
+					processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+				} else if process.Command.Name == enums.DESTROY_SCHEDULE {
+					if err = schedule.Destroy(ctx, &db); err != nil {
+						log.Println(err)
+
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+						if err != nil {
+							log.Fatal(err)
+						}
+					} else {
+						if err = bot.SendMessage(
+							update.Message.Chat.ChatId,
+							"Регулярная операция успешно удалена!",
+						); err != nil {
+							log.Fatal(err)
+						}
+					}
+
+					processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+				} else {
+					fields := []string{"Сумма", "Комментарий", "Расписание"}
+
+					bot.ReplyKeyboard.Create(fields)
+
+					if err = bot.SendMessage(update.Message.Chat.ChatId, "Что будем изменять?"); err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+					processing.Create(
+						update.Message.Chat.ChatId,
+						update.Message.From.UserId,
+						models.Command{
+							Name: enums.EDIT_SCHEDULE,
+							Step: 1,
+						},
+						models.Extra{
+							Schedule: schedule,
+							Keyboard: fields,
+						},
+					)
+				}
+			} else if process.Command.Step == 1 {
+				var text string
+
+				switch update.Message.Text {
+				case "Сумма":
+					text = "Введи новую сумму"
+				case "Комментарий":
+					text = "Введи новый комментарий"
+				case "Расписание":
+					text = "Введи новое расписание. Например: «каждый месяц 5 числа в 10:00», " +
+						"«каждый понедельник» или cron-выражение «0 9 5 * *»"
+				}
+
+				if text == "" {
+					bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+					err := bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.INCORRECT_VALUE])
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+				} else {
+					if err := bot.SendMessage(update.Message.Chat.ChatId, text); err != nil {
+						log.Fatal(err)
+					}
+
+					processing.Create(
+						update.Message.Chat.ChatId,
+						update.Message.From.UserId,
+						models.Command{
+							Name: enums.EDIT_SCHEDULE,
+							Step: 2,
+						},
+						models.Extra{
+							Schedule: process.Extra.Schedule,
+							Keyboard: []string{update.Message.Text},
+						},
+					)
+				}
+			} else if process.Command.Step == 2 {
+				fields := bson.M{}
+
+				switch process.Extra.Keyboard[0] {
+				case "Сумма":
+					amount, err := strconv.Atoi(update.Message.Text)
+					if err != nil || amount <= 0 {
+						log.Println(err)
+
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.INCORRECT_VALUE])
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						return
+					}
+
+					fields["amount"] = amount
+				case "Комментарий":
+					fields["comment"] = update.Message.Text
+				case "Расписание":
+					cron, err := utils.ParseScheduleRule(update.Message.Text)
+					if err == nil {
+						fields["next_run"], err = utils.GetNextRun(cron, time.Now())
+					}
+					if err != nil {
+						log.Println(err)
+
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.INCORRECT_SCHEDULE])
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						return
+					}
+
+					fields["rule"] = update.Message.Text
+					fields["cron"] = cron
+				}
+
+				if err := process.Extra.Schedule.Update(ctx, &db, fields); err != nil {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+					if err != nil {
+						log.Fatal(err)
+					}
+				} else {
+					if err = bot.SendMessage(
+						update.Message.Chat.ChatId,
+						"Регулярная операция изменена!%0A%0A"+
+							utils.GetScheduleDescription(ctx, &db, process.Extra.Schedule),
+					); err != nil {
+						log.Fatal(err)
+					}
+				}
+
+				processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+			}
+			// -------------------------------------------------------------------------------------------------
+		} else if process.Command.Name == enums.SET_DIGEST {
+			// ----------------------------------------------------- handle update in /digest command processing
+			if process.Command.Step == 0 {
+				settings, err := utils.GetSettings(ctx, &db, update.Message.Chat.ChatId)
+				if err != nil {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+
+					return
+				}
+
+				switch update.Message.Text {
+				case "Ежедневно":
+					settings.Digest = enums.Periods[enums.DAY]
+				case "Еженедельно":
+					settings.Digest = enums.Periods[enums.WEEK]
+				case "Отключить":
+					if err = settings.Update(ctx, &db, bson.M{"digest": ""}); err != nil {
+						log.Println(err)
+
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+						if err != nil {
+							log.Fatal(err)
+						}
+					} else {
+						if err = bot.SendMessage(update.Message.Chat.ChatId, "Сводка отключена"); err != nil {
+							log.Fatal(err)
+						}
+					}
+
+					processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+
+					return
+				default:
+					bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.INCORRECT_VALUE])
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+
+					return
+				}
+
+				if err = bot.SendMessage(
+					update.Message.Chat.ChatId,
+					"В котором часу присылать сводку? Напиши число от 0 до 23",
+				); err != nil {
+					log.Fatal(err)
+				}
+
+				processing.Create(
+					update.Message.Chat.ChatId,
+					update.Message.From.UserId,
+					models.Command{
+						Name: enums.SET_DIGEST,
+						Step: 1,
+					},
+					models.Extra{
+						Settings: settings,
+					},
+				)
+			} else if process.Command.Step == 1 {
+				hour, err := strconv.Atoi(update.Message.Text)
+				if err != nil || hour < 0 || hour > 23 {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.INCORRECT_VALUE])
+					if err != nil {
+						log.Fatal(err)
+					}
+				} else {
+					timezones := []string{
+						"Europe/Kaliningrad",
+						"Europe/Moscow",
+						"Europe/Samara",
+						"Asia/Yekaterinburg",
+						"Asia/Novosibirsk",
+						"Asia/Vladivostok",
+					}
+
+					bot.ReplyKeyboard.Create(timezones)
+
+					if err = bot.SendMessage(
+						update.Message.Chat.ChatId,
+						"В каком ты часовом поясе? Выбери его или напиши смещение от UTC, например %2B3",
+					); err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+
+					process.Extra.Settings.DigestHour = hour
+					processing.Create(
+						update.Message.Chat.ChatId,
+						update.Message.From.UserId,
+						models.Command{
+							Name: enums.SET_DIGEST,
+							Step: 2,
+						},
+						models.Extra{
+							Settings: process.Extra.Settings,
+						},
+					)
+				}
+			} else if process.Command.Step == 2 {
+				if _, err := utils.GetLocation(update.Message.Text); err != nil {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+					if err != nil {
+						log.Fatal(err)
+					}
+				} else {
+					bot.ReplyKeyboard.Create([]string{"Без тихих часов"})
+
+					if err = bot.SendMessage(
+						update.Message.Chat.ChatId,
+						"В какие часы тебя не беспокоить? Напиши, например, 23-8",
+					); err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+
+					process.Extra.Settings.Timezone = update.Message.Text
+					processing.Create(
+						update.Message.Chat.ChatId,
+						update.Message.From.UserId,
+						models.Command{
+							Name: enums.SET_DIGEST,
+							Step: 3,
+						},
+						models.Extra{
+							Settings: process.Extra.Settings,
+						},
+					)
+				}
+			} else if process.Command.Step == 3 {
+				var quietFrom, quietTo int
+
+				if update.Message.Text != "Без тихих часов" {
+					hours := strings.SplitN(update.Message.Text, "-", 2)
+
+					var err error
+					if len(hours) == 2 {
+						if quietFrom, err = strconv.Atoi(strings.TrimSpace(hours[0])); err == nil {
+							quietTo, err = strconv.Atoi(strings.TrimSpace(hours[1]))
+						}
+					}
+
+					if len(hours) != 2 || err != nil || quietFrom < 0 || quietFrom > 23 || quietTo < 0 || quietTo > 23 {
+						log.Println(err)
+
+						bot.ReplyKeyboard.Create([]string{"Без тихих часов"})
+
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.INCORRECT_VALUE])
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						bot.ReplyKeyboard.Destroy()
+
+						return
+					}
+				}
+
+				if err := process.Extra.Settings.Update(ctx, &db, bson.M{
+					"digest":      process.Extra.Settings.Digest,
+					"digest_hour": process.Extra.Settings.DigestHour,
+					"timezone":    process.Extra.Settings.Timezone,
+					"quiet_from":  quietFrom,
+					"quiet_to":    quietTo,
+				}); err != nil {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+					if err != nil {
+						log.Fatal(err)
+					}
+				} else {
+					text := "Буду присылать сводку ежедневно"
+					if process.Extra.Settings.Digest == enums.Periods[enums.WEEK] {
+						text = "Буду присылать сводку по понедельникам"
+					}
+
+					if err = bot.SendMessage(
+						update.Message.Chat.ChatId,
+						text+" после "+strconv.Itoa(process.Extra.Settings.DigestHour)+":00 ("+
+							url.QueryEscape(process.Extra.Settings.Timezone)+")",
+					); err != nil {
+						log.Fatal(err)
+					}
+				}
+
+				processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+			}
+			// -------------------------------------------------------------------------------------------------
+		} else if process.Command.Name == enums.GET_HISTORY {
+			// ---------------------------------------------------- handle update in /history command processing
+			if process.Command.Step == 0 {
+				var filter models.Filter
+
+				if update.Message.Text != "Все копилки" {
+					bank, err := utils.GetBank(ctx, &db, update.Message.Chat.ChatId, update.Message.Text)
+					if err == nil {
+						err = utils.CheckRole(bank, update.Message.Chat.ChatId, enums.OWNER)
+					}
+					if err != nil {
+						log.Println(err)
+
+						text := enums.UserErrors[enums.BANK_NOT_FOUND]
+						if err.Error() == enums.UserErrors[enums.NO_PERMISSION] {
+							text = err.Error()
+						}
+
+						bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+						err = bot.SendMessage(update.Message.Chat.ChatId, text)
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						bot.ReplyKeyboard.Destroy()
+
+						return
+					}
+
+					filter.Bank = bank.Id
+				}
+
+				types := []string{
+					"Все операции",
+					enums.OperationNames[enums.INCOME],
+					enums.OperationNames[enums.EXPENSE],
+					enums.OperationNames[enums.CREATE_TRANSFER],
+				}
+
+				bot.ReplyKeyboard.Create(types)
+
+				if err := bot.SendMessage(update.Message.Chat.ChatId, "Какие операции показать?"); err != nil {
+					log.Fatal(err)
+				}
+
+				bot.ReplyKeyboard.Destroy()
+				processing.Create(
+					update.Message.Chat.ChatId,
+					update.Message.From.UserId,
+					models.Command{
+						Name: enums.GET_HISTORY,
+						Step: 1,
+					},
+					models.Extra{
+						Filter:   filter,
+						Keyboard: types,
+					},
+				)
+			} else if process.Command.Step == 1 {
+				var operation string
+
+				for key, name := range enums.OperationNames {
+					if name == update.Message.Text {
+						operation = enums.BotCommands[key]
+					}
+				}
+
+				if operation == "" && update.Message.Text != "Все операции" {
+					bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+					err := bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.INCORRECT_VALUE])
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+				} else {
+					periods := []string{
+						enums.PeriodFilters[enums.ALL_TIME],
+						enums.PeriodFilters[enums.THIS_WEEK],
+						enums.PeriodFilters[enums.THIS_MONTH],
+						enums.PeriodFilters[enums.LAST_MONTH],
+					}
+
+					bot.ReplyKeyboard.Create(periods)
+
+					if err := bot.SendMessage(
+						update.Message.Chat.ChatId,
+						"За какой период? Выбери его или напиши в формате ДД.ММ.ГГГГ-ДД.ММ.ГГГГ",
+					); err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+
+					process.Extra.Filter.Type = operation
+					processing.Create(
+						update.Message.Chat.ChatId,
+						update.Message.From.UserId,
+						models.Command{
+							Name: enums.GET_HISTORY,
+							Step: 2,
+						},
+						models.Extra{
+							Filter:   process.Extra.Filter,
+							Keyboard: periods,
+						},
+					)
+				}
+			} else if process.Command.Step == 2 {
+				from, to, err := utils.ParsePeriod(update.Message.Text, time.Now())
+				if err != nil {
+					log.Println(err)
+
+					bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+
+					return
+				}
+
+				process.Extra.Filter.From = from
+				process.Extra.Filter.To = to
+
+				history, buttons, err := utils.GetHistory(ctx, &db, update.Message.Chat.ChatId, process.Extra.Filter, 0)
+				if err != nil {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+					if err != nil {
+						log.Fatal(err)
+					}
+				} else {
+					for _, row := range buttons {
+						bot.InlineKeyboard.Create(row)
+					}
+
+					if err = bot.SendMessage(update.Message.Chat.ChatId, history); err != nil {
+						log.Fatal(err)
+					}
+
+					bot.InlineKeyboard.Destroy()
+				}
+
+				processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+			}
+			// -------------------------------------------------------------------------------------------------
+		} else if process.Command.Name == enums.EDIT_OPERATION {
+			// --------------------------------------------------- handle update in operation editing processing
+			edited := process.Extra.Operation
+
+			switch process.Extra.Field {
+			case enums.OperationFields[enums.AMOUNT]:
+				amount, err := strconv.Atoi(update.Message.Text)
+				if err != nil || amount <= 0 {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.INCORRECT_VALUE])
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					return
+				}
+
+				edited.Amount = amount
+			case enums.OperationFields[enums.COMMENT]:
+				edited.Comment = update.Message.Text
+			case enums.OperationFields[enums.DATE]:
+				createdAt := utils.ParseCreatedAt(edited.CreatedAt).In(time.Local)
+
+				date, err := time.ParseInLocation("02.01.2006 15:04", update.Message.Text, time.Local)
+				if err != nil {
+					date, err = time.ParseInLocation("02.01.2006", update.Message.Text, time.Local)
+					date = date.Add(time.Duration(createdAt.Hour())*time.Hour + time.Duration(createdAt.Minute())*time.Minute)
+				}
+				if err != nil {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.INCORRECT_DATE])
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					return
+				}
+
+				edited.CreatedAt = date.String()
+			case enums.OperationFields[enums.BANK]:
+				bank, err := utils.GetBank(ctx, &db, update.Message.Chat.ChatId, update.Message.Text)
+				if err == nil {
+					err = utils.CheckRole(bank, update.Message.Chat.ChatId, enums.OWNER)
+				}
+				if err != nil {
+					log.Println(err)
+
+					bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+					if utils.IsUserError(err) {
+						err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+					} else {
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+					}
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+
+					return
+				}
+
+				edited.Bank = bank.Id
+			}
+
+			if err := utils.UpdateOperation(ctx, &db, &process.Extra.Operation, edited, &update.Message.From); err != nil {
+				log.Println(err)
+
+				if utils.IsUserError(err) {
+					err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+				} else {
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+				}
+				if err != nil {
+					log.Fatal(err)
+				}
+			} else {
+				if err = bot.SendMessage(
+					update.Message.Chat.ChatId,
+					"Операция изменена!%0A%0A"+utils.GetOperationDescription(ctx, &db, &process.Extra.Operation),
+				); err != nil {
+					log.Fatal(err)
+				}
+			}
+
+			processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+			// -------------------------------------------------------------------------------------------------
+		} else if process.Command.Name == enums.GET_REPORT {
+			// ----------------------------------------------------- handle update in /report command processing
+			from, to, err := utils.ParsePeriod(update.Message.Text, time.Now())
+			if err != nil || from.IsZero() {
+				log.Println(err)
+
+				bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+				err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.INCORRECT_PERIOD])
+				if err != nil {
+					log.Fatal(err)
+				}
+
+				bot.ReplyKeyboard.Destroy()
+
+				return
+			}
+
+			if report, err := utils.GetReport(ctx, &db, update.Message.Chat.ChatId, from, to); err != nil {
+				log.Println(err)
+
+				if utils.IsUserError(err) {
+					err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+				} else {
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+				}
+				if err != nil {
+					log.Fatal(err)
+				}
+			} else {
+				if err = bot.SendMessage(update.Message.Chat.ChatId, report); err != nil {
+					log.Fatal(err)
+				}
+			}
+
+			processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+			// -------------------------------------------------------------------------------------------------
+		} else if process.Command.Name == enums.SEARCH {
+			// ----------------------------------------------------- handle update in /search command processing
+			if result, err := utils.GetSearchResult(ctx, &db, update.Message.Chat.ChatId, update.Message.Text); err != nil {
+				log.Println(err)
+
+				err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+				if err != nil {
+					log.Fatal(err)
+				}
+			} else {
+				if err = bot.SendMessage(update.Message.Chat.ChatId, result); err != nil {
+					log.Fatal(err)
+				}
+			}
+
+			processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+			// -------------------------------------------------------------------------------------------------
+		} else if process.Command.Name == enums.RENAME_BANK {
+			// ------------------------------------------------ handle update in /rename_bank command processing
+			if process.Command.Step == 0 {
+				if bank, err := utils.GetBank(ctx, &db, update.Message.Chat.ChatId, update.Message.Text); err != nil {
+					log.Println(err)
+
+					if err.Error() == enums.UserErrors[enums.BANK_NOT_FOUND] {
+						bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+						err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						bot.ReplyKeyboard.Destroy()
+					} else {
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+					}
+				} else if err = utils.CheckRole(bank, update.Message.Chat.ChatId, enums.ADMIN); err != nil {
+					log.Println(err)
+
+					bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+				} else {
+					if err = bot.SendMessage(
+						update.Message.Chat.ChatId,
+						"Как теперь будет называться копилка?",
+					); err != nil {
+						log.Fatal(err)
+					}
+
+					processing.Create(
+						update.Message.Chat.ChatId,
+						update.Message.From.UserId,
+						models.Command{
+							Name: enums.RENAME_BANK,
+							Step: 1,
+						},
+						models.Extra{
+							Bank: bank,
+						},
+					)
+				}
+			} else if process.Command.Step == 1 {
+				previous := process.Extra.Bank.Name
+
+				if err := utils.RenameBank(ctx, &db, process.Extra.Bank, update.Message.Text); err != nil {
+					log.Println(err)
+
+					if utils.IsUserError(err) {
+						err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+						if err != nil {
+							log.Fatal(err)
+						}
+					} else {
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+					}
+				} else {
+					if err = bot.SendMessage(
+						update.Message.Chat.ChatId,
+						"Копилка успешно переименована!",
+					); err != nil {
+						log.Fatal(err)
+					}
+
+					notifyMembers(
+						process.Extra.Bank,
+						update.Message.Chat.ChatId,
+						url.QueryEscape(update.Message.From.Name())+" · копилка "+previous+" переименована в "+process.Extra.Bank.Name,
+					)
+
+					processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+				}
+			}
+			// -------------------------------------------------------------------------------------------------
+		} else if process.Command.Name == enums.MERGE_BANKS {
+			// ------------------------------------------------ handle update in /merge_banks command processing
+			bank, err := utils.GetBank(ctx, &db, update.Message.Chat.ChatId, update.Message.Text)
+			if err == nil {
+				err = utils.CheckRole(bank, update.Message.Chat.ChatId, enums.OWNER)
+			}
+			if err != nil {
+				log.Println(err)
+
+				if utils.IsUserError(err) {
+					bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+				} else {
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+				}
+
+				return
+			}
+
+			if process.Command.Step == 0 {
+				var keyboard []string
+				for _, name := range process.Extra.Keyboard {
+					if name != bank.Name {
+						keyboard = append(keyboard, name)
+					}
+				}
+
+				bot.ReplyKeyboard.Create(keyboard)
+
+				if err = bot.SendMessage(
+					update.Message.Chat.ChatId,
+					"В какую копилку перенести баланс и операции копилки "+bank.Name+"?",
+				); err != nil {
+					log.Fatal(err)
+				}
+
+				bot.ReplyKeyboard.Destroy()
+				processing.Create(
+					update.Message.Chat.ChatId,
+					update.Message.From.UserId,
+					models.Command{
+						Name: enums.MERGE_BANKS,
+						Step: 1,
+					},
+					models.Extra{
+						Bank:     bank,
+						Keyboard: keyboard,
+					},
+				)
+			} else if process.Command.Step == 1 {
+				if err = utils.MergeBanks(ctx, &db, process.Extra.Bank, bank); err != nil {
+					log.Println(err)
+
+					if utils.IsUserError(err) {
+						bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+						err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						bot.ReplyKeyboard.Destroy()
+					} else {
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+					}
+				} else {
+					if err = bot.SendMessage(
+						update.Message.Chat.ChatId,
+						"Копилки успешно объединены!%0AБаланс копилки "+bank.Name+" составляет "+
+							strconv.Itoa(bank.Balance)+" руб.",
+					); err != nil {
+						log.Fatal(err)
+					}
+
+					processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+				}
+			}
+			// -------------------------------------------------------------------------------------------------
+		} else if process.Command.Name == enums.MOVE_BANK {
+			// -------------------------------------------------- handle update in /move_bank command processing
+			var bank *models.Bank
+
+			if process.Command.Step == 0 || update.Message.Text != "Без родительской копилки" {
+				var err error
+
+				bank, err = utils.GetBank(ctx, &db, update.Message.Chat.ChatId, update.Message.Text)
+				if err == nil {
+					err = utils.CheckRole(bank, update.Message.Chat.ChatId, enums.OWNER)
+				}
+				if err != nil {
+					log.Println(err)
+
+					if utils.IsUserError(err) {
+						bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+						err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						bot.ReplyKeyboard.Destroy()
+					} else {
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+					}
+
+					return
+				}
+			}
+
+			if process.Command.Step == 0 {
+				bankNames, err := utils.GetBankNames(ctx, &db, update.Message.Chat.ChatId)
+				if err != nil {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+
+					return
+				}
+
+				keyboard := append([]string{"Без родительской копилки"}, bankNames...)
+
+				bot.ReplyKeyboard.Create(keyboard)
+
+				if err = bot.SendMessage(
+					update.Message.Chat.ChatId,
+					"В какую копилку вложить копилку "+bank.Name+"?",
+				); err != nil {
+					log.Fatal(err)
+				}
+
+				bot.ReplyKeyboard.Destroy()
+				processing.Create(
+					update.Message.Chat.ChatId,
+					update.Message.From.UserId,
+					models.Command{
+						Name: enums.MOVE_BANK,
+						Step: 1,
+					},
+					models.Extra{
+						Bank:     bank,
+						Keyboard: keyboard,
+					},
+				)
+			} else if process.Command.Step == 1 {
+				if err := utils.SetBankParent(ctx, &db, process.Extra.Bank, bank); err != nil {
+					log.Println(err)
+
+					if utils.IsUserError(err) {
+						bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+						err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						bot.ReplyKeyboard.Destroy()
+					} else {
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+					}
+				} else {
+					if err = bot.SendMessage(
+						update.Message.Chat.ChatId,
+						"Копилка успешно перемещена!",
+					); err != nil {
+						log.Fatal(err)
+					}
+
+					processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+				}
+			}
+			// -------------------------------------------------------------------------------------------------
+		} else if process.Command.Name == enums.IMPORT_STATEMENT {
+			// ---------------------------------------------------- handle update in statement import processing
+			if process.Command.Step == 0 {
+				if columns, err := utils.ParseColumns(update.Message.Text, len(process.Extra.Records[0])); err != nil {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+					if err != nil {
+						log.Fatal(err)
+					}
+				} else if operations, preview, err := utils.GetStatementImport(
+					ctx,
+					&db,
+					update.Message.Chat.ChatId,
+					process.Extra.Records,
+					columns,
+				); err != nil {
+					log.Println(err)
+
+					if !utils.IsUserError(err) {
+						err = errors.New(enums.UserErrors[enums.UNEXPECTED_ERROR])
+					}
+
+					if err = bot.SendMessage(update.Message.Chat.ChatId, err.Error()); err != nil {
+						log.Fatal(err)
+					}
+
+					processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+				} else if bankNames, err := utils.GetBankNames(ctx, &db, update.Message.Chat.ChatId); err != nil {
+					bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+
+					processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+				} else {
+					keyboard := append([]string{"Для каждой строки отдельно"}, bankNames...)
+
+					bot.ReplyKeyboard.Create(keyboard)
+
+					if err = bot.SendMessage(
+						update.Message.Chat.ChatId,
+						preview+"%0AВ какую копилку записать операции?",
+					); err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+					processing.Create(
+						update.Message.Chat.ChatId,
+						update.Message.From.UserId,
+						models.Command{
+							Name: enums.IMPORT_STATEMENT,
+							Step: 1,
+						},
+						models.Extra{
+							Operations: operations,
+							Keyboard:   keyboard,
+						},
+					)
+				}
+			} else if process.Command.Step == 1 || process.Command.Step == 2 {
+				operations := process.Extra.Operations
+
+				if process.Command.Step == 1 && update.Message.Text == "Для каждой строки отдельно" {
+					if err := utils.MatchBanks(ctx, &db, update.Message.Chat.ChatId, operations); err != nil {
+						log.Println(err)
+
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+
+						return
+					}
+				} else if process.Command.Step == 2 && update.Message.Text == "Пропустить" {
+					index := utils.GetUnmatchedOperation(operations)
+					operations = append(operations[:index:index], operations[index+1:]...)
+				} else {
+					bank, err := utils.GetBank(ctx, &db, update.Message.Chat.ChatId, update.Message.Text)
+					if err == nil {
+						err = utils.CheckRole(bank, update.Message.Chat.ChatId, enums.OWNER)
+					}
+					if err != nil {
+						log.Println(err)
+
+						if utils.IsUserError(err) {
+							bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+							err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+							if err != nil {
+								log.Fatal(err)
+							}
+
+							bot.ReplyKeyboard.Destroy()
+						} else {
+							err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+							if err != nil {
+								log.Fatal(err)
+							}
+
+							processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+						}
+
+						return
+					}
+
+					// the chosen bank is also used for the other rows with the same description
+					index := utils.GetUnmatchedOperation(operations)
+					for current := range operations {
+						if operations[current].Bank == "" && (process.Command.Step == 1 ||
+							strings.EqualFold(operations[current].Comment, operations[index].Comment)) {
+							operations[current].Bank = bank.Id
+						}
+					}
+				}
+
+				if index := utils.GetUnmatchedOperation(operations); index >= 0 {
+					bankNames, err := utils.GetBankNames(ctx, &db, update.Message.Chat.ChatId)
+					if err != nil {
+						log.Println(err)
+
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+
+						return
+					}
+
+					keyboard := append(bankNames, "Пропустить")
+
+					bot.ReplyKeyboard.Create(keyboard)
+
+					if err = bot.SendMessage(
+						update.Message.Chat.ChatId,
+						utils.GetStatementRow(operations[index])+"%0A%0AВ какую копилку записать эту операцию?",
+					); err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+					processing.Create(
+						update.Message.Chat.ChatId,
+						update.Message.From.UserId,
+						models.Command{
+							Name: enums.IMPORT_STATEMENT,
+							Step: 2,
+						},
+						models.Extra{
+							Operations: operations,
+							Keyboard:   keyboard,
+						},
+					)
+				} else if banks, err := utils.ImportOperations(ctx, &db, update.Message.Chat.ChatId, operations, &update.Message.From); err != nil {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+				} else {
+					text := "Импортировано операций: " + strconv.Itoa(len(operations))
+					for _, bank := range banks {
+						text += "%0AБаланс копилки " + bank.Name + " составляет " + strconv.Itoa(bank.Balance) + " руб."
+					}
+
+					if err = bot.SendMessage(update.Message.Chat.ChatId, text); err != nil {
+						log.Fatal(err)
+					}
+
+					processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+				}
+			}
+			// -------------------------------------------------------------------------------------------------
+		} else if process.Command.Name == enums.EXPORT {
+			// ----------------------------------------------------- handle update in /export command processing
+			if process.Command.Step == 0 {
+				from, to, err := utils.ParsePeriod(update.Message.Text, time.Now())
+				if err != nil {
+					log.Println(err)
+
+					bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.INCORRECT_PERIOD])
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+
+					return
+				}
+
+				formats := []string{"CSV", "XLSX", "hledger", "beancount"}
+
+				bot.ReplyKeyboard.Create(formats)
+
+				if err = bot.SendMessage(update.Message.Chat.ChatId, "В каком формате?"); err != nil {
+					log.Fatal(err)
+				}
+
+				bot.ReplyKeyboard.Destroy()
+				processing.Create(
+					update.Message.Chat.ChatId,
+					update.Message.From.UserId,
+					models.Command{
+						Name: enums.EXPORT,
+						Step: 1,
+					},
+					models.Extra{
+						Filter: models.Filter{
+							From: from,
+							To:   to,
+						},
+						Keyboard: formats,
+					},
+				)
+			} else if process.Command.Step == 1 {
+				sheets, err := utils.GetExport(
+					ctx,
+					&db,
+					update.Message.Chat.ChatId,
+					process.Extra.Filter.From,
+					process.Extra.Filter.To,
+				)
+				if err != nil {
+					log.Println(err)
+
+					if utils.IsUserError(err) {
+						err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+					} else {
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+					}
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+
+					return
+				}
+
+				name := "bieas_" + time.Now().Format("2006-01-02")
+				if !process.Extra.Filter.From.IsZero() {
+					name = "bieas_" + process.Extra.Filter.From.Format("2006-01-02") + "_" +
+						process.Extra.Filter.To.AddDate(0, 0, -1).Format("2006-01-02")
+				}
+
+				var data []byte
+
+				switch update.Message.Text {
+				case "CSV":
+					name += ".csv"
+					data, err = utils.WriteCSV(sheets[0].Rows)
+				case "XLSX":
+					name += ".xlsx"
+					data, err = utils.WriteXLSX(sheets)
+				case "hledger", "beancount":
+					var ledger string
+
+					ledger, err = utils.GetLedger(
+						ctx,
+						&db,
+						update.Message.Chat.ChatId,
+						process.Extra.Filter.From,
+						process.Extra.Filter.To,
+						update.Message.Text == "beancount",
+					)
+
+					name += map[string]string{"hledger": ".journal", "beancount": ".beancount"}[update.Message.Text]
+					data = []byte(ledger)
+				default:
+					bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.INCORRECT_VALUE])
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+
+					return
+				}
+
+				if err != nil {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
 					if err != nil {
 						log.Fatal(err)
 					}
+				} else if err = bot.SendDocument(update.Message.Chat.ChatId, name, data); err != nil {
+					log.Fatal(err)
+				}
+
+				processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+			}
+			// -------------------------------------------------------------------------------------------------
+		} else if process.Command.Name == enums.SET_LEDGER_ACCOUNTS {
+			// -------------------------------------------- handle update in /ledger_accounts command processing
+			name, err := utils.ParseLedgerAccount(update.Message.Text)
+			if err != nil {
+				log.Println(err)
+
+				err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+				if err != nil {
+					log.Fatal(err)
+				}
+
+				return
+			}
+
+			if process.Command.Step == 0 {
+				expense := process.Extra.Settings.Expense
+				if expense == "" {
+					expense = "Expenses:Прочее"
+				}
+
+				bot.ReplyKeyboard.Create([]string{expense})
 
-					processing.Destroy(update.Message.Chat.ChatId)
+				if err = bot.SendMessage(update.Message.Chat.ChatId, "А на какой счёт записывать расходы?"); err != nil {
+					log.Fatal(err)
 				}
-			} else {
-				err = bank.Destroy(ctx, &db)
-				if err != nil {
+
+				bot.ReplyKeyboard.Destroy()
+
+				process.Extra.Settings.Income = name
+
+				processing.Create(
+					update.Message.Chat.ChatId,
+					update.Message.From.UserId,
+					models.Command{
+						Name: enums.SET_LEDGER_ACCOUNTS,
+						Step: 1,
+					},
+					models.Extra{
+						Settings: process.Extra.Settings,
+					},
+				)
+			} else if process.Command.Step == 1 {
+				if err = process.Extra.Settings.Update(ctx, &db, bson.M{
+					"income_account":  process.Extra.Settings.Income,
+					"expense_account": name,
+				}); err != nil {
 					log.Println(err)
 
 					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
@@ -298,50 +4935,127 @@ func handler(update models.Update) {
 				} else {
 					if err = bot.SendMessage(
 						update.Message.Chat.ChatId,
-						"Копилка успешно удалена!",
+						"Счета сохранены! Доходы: "+url.QueryEscape(process.Extra.Settings.Income)+
+							", расходы: "+url.QueryEscape(name),
 					); err != nil {
 						log.Fatal(err)
 					}
 				}
 
-				processing.Destroy(update.Message.Chat.ChatId)
+				processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
 			}
 			// -------------------------------------------------------------------------------------------------
-		} else if process.Command.Name == enums.GET_BALANCE {
-			// ------------------------------------------------ handle update in /get_balance command processing
-			if bank, err := utils.GetBank(ctx, &db, update.Message.Chat.ChatId, update.Message.Text); err != nil {
-				log.Println(err)
+		} else if process.Command.Name == enums.CHART {
+			// ------------------------------------------------------ handle update in /chart command processing
+			if process.Command.Step == 0 {
+				var chart enums.Chart
+				for kind, name := range enums.ChartNames {
+					if kind != enums.UndefinedChart && name == update.Message.Text {
+						chart = kind
+					}
+				}
 
-				if err.Error() == enums.UserErrors[enums.BANK_NOT_FOUND] {
+				if chart == enums.UndefinedChart {
 					bot.ReplyKeyboard.Create(process.Extra.Keyboard)
 
-					err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+					err := bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.INCORRECT_VALUE])
 					if err != nil {
 						log.Fatal(err)
 					}
 
 					bot.ReplyKeyboard.Destroy()
-				} else {
-					err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+
+					return
+				}
+
+				periods := []string{
+					enums.PeriodFilters[enums.THIS_MONTH],
+					enums.PeriodFilters[enums.LAST_MONTH],
+					enums.PeriodFilters[enums.ALL_TIME],
+				}
+
+				bot.ReplyKeyboard.Create(periods)
+
+				if err := bot.SendMessage(
+					update.Message.Chat.ChatId,
+					"За какой период? Выбери его, напиши месяц в формате ММ.ГГГГ "+
+						"или период в формате ДД.ММ.ГГГГ-ДД.ММ.ГГГГ",
+				); err != nil {
+					log.Fatal(err)
+				}
+
+				bot.ReplyKeyboard.Destroy()
+				processing.Create(
+					update.Message.Chat.ChatId,
+					update.Message.From.UserId,
+					models.Command{
+						Name: enums.CHART,
+						Step: 1,
+					},
+					models.Extra{
+						Chart:    chart,
+						Keyboard: periods,
+					},
+				)
+			} else if process.Command.Step == 1 {
+				from, to, err := utils.ParsePeriod(update.Message.Text, time.Now())
+				if err != nil {
+					log.Println(err)
+
+					bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.INCORRECT_PERIOD])
 					if err != nil {
 						log.Fatal(err)
 					}
 
-					processing.Destroy(update.Message.Chat.ChatId)
+					bot.ReplyKeyboard.Destroy()
+
+					return
 				}
-			} else {
-				if err = bot.SendMessage(
-					update.Message.Chat.ChatId,
-					"Баланс копилки "+bank.Name+" составляет "+strconv.Itoa(bank.Balance)+" руб.",
-				); err != nil {
+
+				// all the time ends today
+				if to.IsZero() {
+					now := time.Now()
+					to = time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+				}
+
+				var chart *models.Chart
+
+				switch process.Extra.Chart {
+				case enums.BALANCE_CHART:
+					chart, err = utils.GetBalanceChart(ctx, &db, update.Message.Chat.ChatId, from, to)
+				case enums.EXPENSES_CHART:
+					chart, err = utils.GetExpensesChart(ctx, &db, update.Message.Chat.ChatId, from, to)
+				default:
+					chart, err = utils.GetMonthlyChart(ctx, &db, update.Message.Chat.ChatId, from, to)
+				}
+
+				var data []byte
+				if err == nil {
+					data, err = utils.DrawChart(chart, process.Extra.Chart)
+				}
+
+				if err != nil {
+					log.Println(err)
+
+					if utils.IsUserError(err) {
+						err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+					} else {
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+					}
+					if err != nil {
+						log.Fatal(err)
+					}
+				} else if err = bot.SendPhoto(update.Message.Chat.ChatId, "chart.png", data); err != nil {
 					log.Fatal(err)
 				}
 
-				processing.Destroy(update.Message.Chat.ChatId)
+				processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
 			}
 			// -------------------------------------------------------------------------------------------------
-		} else if process.Command.Name == enums.INCOME {
-			// ----------------------------------------------------- handle update in /income command processing
+		} else if process.Command.Name == enums.SHARE_BANK {
+			// ------------------------------------------------- handle update in /share_bank command processing
 			if process.Command.Step == 0 {
 				if bank, err := utils.GetBank(ctx, &db, update.Message.Chat.ChatId, update.Message.Text); err != nil {
 					log.Println(err)
@@ -361,105 +5075,223 @@ func handler(update models.Update) {
 							log.Fatal(err)
 						}
 
-						processing.Destroy(update.Message.Chat.ChatId)
+						processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
 					}
-				} else {
-					err = bot.SendMessage(update.Message.Chat.ChatId, "На какую сумму?")
+				} else if err = utils.CheckRole(bank, update.Message.Chat.ChatId, enums.ADMIN); err != nil {
+					log.Println(err)
+
+					bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
 					if err != nil {
 						log.Fatal(err)
 					}
 
+					bot.ReplyKeyboard.Destroy()
+				} else {
+					roles := []string{
+						enums.RoleNames[enums.VIEWER],
+						enums.RoleNames[enums.REQUESTER],
+						enums.RoleNames[enums.CONTRIBUTOR],
+						enums.RoleNames[enums.ADMIN],
+					}
+
+					bot.ReplyKeyboard.Create(roles)
+
+					if err = bot.SendMessage(
+						update.Message.Chat.ChatId,
+						"Что сможет делать новый участник? Просмотр — видеть баланс, запросы на одобрение — ещё и "+
+							"просить доходы и расходы, которые изменят баланс после одобрения администратором, внесение операций — "+
+							"записывать доходы, расходы и переводы сразу, администратор — ещё и одобрять запросы, менять цель, "+
+							"лимит и название копилки и приглашать других",
+					); err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
 					processing.Create(
 						update.Message.Chat.ChatId,
+						update.Message.From.UserId,
 						models.Command{
-							Name: enums.INCOME,
+							Name: enums.SHARE_BANK,
 							Step: 1,
 						},
 						models.Extra{
-							Bank: bank,
-							Operation: models.Operation{
-								Account:   update.Message.Chat.ChatId,
-								Bank:      bank.Id,
-								Operation: enums.BotCommands[enums.INCOME],
-							},
+							Bank:     bank,
+							Keyboard: roles,
 						},
 					)
 				}
 			} else if process.Command.Step == 1 {
-				amount, err := strconv.Atoi(update.Message.Text)
-				if err != nil {
+				var role enums.Role
+				for _, granted := range []enums.Role{enums.VIEWER, enums.REQUESTER, enums.CONTRIBUTOR, enums.ADMIN} {
+					if enums.RoleNames[granted] == update.Message.Text {
+						role = granted
+					}
+				}
+
+				if role == enums.UndefinedRole {
+					bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+					err := bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.INCORRECT_VALUE])
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+
+					return
+				}
+
+				if code, err := utils.CreateInvite(ctx, &db, process.Extra.Bank, role); err != nil {
 					log.Println(err)
 
-					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.INCORRECT_VALUE])
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
 					if err != nil {
 						log.Fatal(err)
 					}
 				} else {
 					if err = bot.SendMessage(
 						update.Message.Chat.ChatId,
-						"Добавь комментарий к операции",
+						"Отправь эту ссылку тому, с кем хочешь вести копилку "+process.Extra.Bank.Name+":%0A"+
+							url.QueryEscape("https://t.me/"+bot.Username+"?start="+code)+"%0A%0A"+
+							"Ссылка сработает только один раз",
 					); err != nil {
 						log.Fatal(err)
 					}
+				}
 
-					processing.Create(
-						update.Message.Chat.ChatId,
-						models.Command{
-							Name: enums.INCOME,
-							Step: 2,
-						},
-						models.Extra{
-							Bank: process.Extra.Bank,
-							Operation: models.Operation{
-								Account:   process.Extra.Operation.Account,
-								Bank:      process.Extra.Operation.Bank,
-								Operation: process.Extra.Operation.Operation,
-								Amount:    amount,
-							},
+				processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+			}
+			// -------------------------------------------------------------------------------------------------
+		} else if process.Command.Name == enums.CREATE_DEBT {
+			// ------------------------------------------------ handle update in /create_debt command processing
+			if process.Command.Step == 0 {
+				var direction enums.DebtDirection
+
+				for key, name := range enums.DebtDirectionNames {
+					if key != enums.UndefinedDebtDirection && name == update.Message.Text {
+						direction = key
+					}
+				}
+
+				if direction == enums.UndefinedDebtDirection {
+					bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+					err := bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.INCORRECT_VALUE])
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					bot.ReplyKeyboard.Destroy()
+
+					return
+				}
+
+				text := "Кто тебе должен? Напиши имя"
+				if direction == enums.BORROWED {
+					text = "Кому ты должен? Напиши имя"
+				}
+
+				if err := bot.SendMessage(update.Message.Chat.ChatId, text); err != nil {
+					log.Fatal(err)
+				}
+
+				processing.Create(
+					update.Message.Chat.ChatId,
+					update.Message.From.UserId,
+					models.Command{
+						Name: enums.CREATE_DEBT,
+						Step: 1,
+					},
+					models.Extra{
+						Debt: &models.Debt{
+							Account:   update.Message.Chat.ChatId,
+							Direction: enums.DebtDirections[direction],
 						},
-					)
+					},
+				)
+			} else if process.Command.Step == 1 {
+				process.Extra.Debt.Person = strings.TrimSpace(update.Message.Text)
+
+				if err := bot.SendMessage(update.Message.Chat.ChatId, "На какую сумму?"); err != nil {
+					log.Fatal(err)
 				}
+
+				processing.Create(
+					update.Message.Chat.ChatId,
+					update.Message.From.UserId,
+					models.Command{
+						Name: enums.CREATE_DEBT,
+						Step: 2,
+					},
+					process.Extra,
+				)
 			} else if process.Command.Step == 2 {
-				err := process.Extra.Operation.Create(ctx, &db)
+				amount, _, err := utils.ParseAmount(update.Message.Text)
 				if err != nil {
 					log.Println(err)
 
-					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+					err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
 					if err != nil {
 						log.Fatal(err)
 					}
-				} else {
-					balance := process.Extra.Bank.Balance + process.Extra.Operation.Amount
 
-					err = process.Extra.Bank.Update(ctx, &db, bson.M{"balance": balance})
-					if err != nil {
-						log.Println(err)
+					return
+				}
 
-						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
-						if err != nil {
-							log.Fatal(err)
-						}
+				process.Extra.Debt.Amount = amount
+
+				bankNames, err := utils.GetBankNames(ctx, &db, update.Message.Chat.ChatId)
+				if err != nil {
+					log.Println(err)
+				}
+
+				keyboard := append(bankNames, "Без копилки")
+
+				text := "Из какой копилки ты дал деньги? Её баланс уменьшится на сумму долга"
+				if process.Extra.Debt.Direction == enums.DebtDirections[enums.BORROWED] {
+					text = "В какую копилку положить деньги? Её баланс увеличится на сумму долга"
+				}
+
+				bot.ReplyKeyboard.Create(keyboard)
+
+				if err = bot.SendMessage(update.Message.Chat.ChatId, text); err != nil {
+					log.Fatal(err)
+				}
+
+				bot.ReplyKeyboard.Destroy()
+				processing.Create(
+					update.Message.Chat.ChatId,
+					update.Message.From.UserId,
+					models.Command{
+						Name: enums.CREATE_DEBT,
+						Step: 3,
+					},
+					models.Extra{
+						Debt:     process.Extra.Debt,
+						Keyboard: keyboard,
+					},
+				)
+			} else if process.Command.Step == 3 {
+				var bank *models.Bank
+				var err error
+
+				if update.Message.Text != "Без копилки" {
+					if bank, err = utils.GetBank(ctx, &db, update.Message.Chat.ChatId, update.Message.Text); err == nil {
+						err = utils.CheckRole(bank, update.Message.Chat.ChatId, enums.CONTRIBUTOR)
 					}
 
-					if err = bot.SendMessage(
-						update.Message.Chat.ChatId,
-						"Баланс копилки был успешно изменен! Текущий баланс: "+
-							strconv.Itoa(process.Extra.Bank.Balance)+" руб.",
-					); err != nil {
-						log.Fatal(err)
+					if err == nil && process.Extra.Debt.Direction == enums.DebtDirections[enums.LENT] &&
+						bank.Overdraft == enums.OverdraftPolicies[enums.FORBID] && bank.Balance < process.Extra.Debt.Amount {
+						err = errors.New(enums.UserErrors[enums.INSUFFICIENT_FUNDS])
 					}
 				}
 
-				processing.Destroy(update.Message.Chat.ChatId)
-			}
-			// -------------------------------------------------------------------------------------------------
-		} else if process.Command.Name == enums.EXPENSE {
-			// ---------------------------------------------------- handle update in /expense command processing
-			if process.Command.Step == 0 {
-				if bank, err := utils.GetBank(ctx, &db, update.Message.Chat.ChatId, update.Message.Text); err != nil {
+				if err != nil {
 					log.Println(err)
 
-					if err.Error() == enums.UserErrors[enums.BANK_NOT_FOUND] {
+					if utils.IsUserError(err) {
 						bot.ReplyKeyboard.Create(process.Extra.Keyboard)
 
 						err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
@@ -474,104 +5306,219 @@ func handler(update models.Update) {
 							log.Fatal(err)
 						}
 
-						processing.Destroy(update.Message.Chat.ChatId)
+						processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+					}
+
+					return
+				}
+
+				if bank != nil {
+					process.Extra.Debt.Bank = bank.Id
+				}
+
+				bot.ReplyKeyboard.Create([]string{"Без срока"})
+
+				if err = bot.SendMessage(
+					update.Message.Chat.ChatId,
+					"До какой даты нужно вернуть долг? Напиши дату в формате ДД.ММ.ГГГГ",
+				); err != nil {
+					log.Fatal(err)
+				}
+
+				bot.ReplyKeyboard.Destroy()
+				processing.Create(
+					update.Message.Chat.ChatId,
+					update.Message.From.UserId,
+					models.Command{
+						Name: enums.CREATE_DEBT,
+						Step: 4,
+					},
+					models.Extra{
+						Bank: bank,
+						Debt: process.Extra.Debt,
+					},
+				)
+			} else if process.Command.Step == 4 {
+				var err error
+
+				if update.Message.Text != "Без срока" {
+					process.Extra.Debt.Due, err = time.ParseInLocation("02.01.2006", update.Message.Text, time.Local)
+					if err != nil || process.Extra.Debt.Due.Before(utils.GetPeriodStart(enums.Periods[enums.DAY], time.Now())) {
+						log.Println(err)
+
+						bot.ReplyKeyboard.Create([]string{"Без срока"})
+
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.INCORRECT_DATE])
+						if err != nil {
+							log.Fatal(err)
+						}
+
+						bot.ReplyKeyboard.Destroy()
+
+						return
+					}
+				}
+
+				if err = utils.CreateDebt(
+					ctx,
+					&db,
+					process.Extra.Debt,
+					process.Extra.Bank,
+					&update.Message.From,
+				); err != nil {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+					if err != nil {
+						log.Fatal(err)
+					}
+				} else {
+					text := "Долг записан!%0A%0A" + utils.GetDebtDescription(ctx, &db, process.Extra.Debt)
+					if process.Extra.Bank != nil {
+						text += "%0A%0AБаланс копилки " + process.Extra.Bank.Name + " составляет " +
+							strconv.Itoa(process.Extra.Bank.Balance) + " руб."
+					}
+
+					if err = bot.SendMessage(update.Message.Chat.ChatId, text); err != nil {
+						log.Fatal(err)
+					}
+				}
+
+				processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+			}
+			// -------------------------------------------------------------------------------------------------
+		} else if process.Command.Name == enums.REPAY_DEBT {
+			// ------------------------------------------------- handle update in /repay_debt command processing
+			if process.Command.Step == 0 {
+				debts, labels, err := utils.GetDebts(ctx, &db, update.Message.Chat.ChatId)
+				if err != nil {
+					log.Println(err)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+
+					return
+				}
+
+				var debt *models.Debt
+
+				for index := range labels {
+					if labels[index] == update.Message.Text {
+						debt = &debts[index]
 					}
-				} else {
-					err = bot.SendMessage(update.Message.Chat.ChatId, "На какую сумму?")
+				}
+
+				if debt == nil {
+					bot.ReplyKeyboard.Create(labels)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.DEBT_NOT_FOUND])
 					if err != nil {
 						log.Fatal(err)
 					}
 
-					processing.Create(
-						update.Message.Chat.ChatId,
-						models.Command{
-							Name: enums.EXPENSE,
-							Step: 1,
-						},
-						models.Extra{
-							Bank: bank,
-							Operation: models.Operation{
-								Account:   update.Message.Chat.ChatId,
-								Bank:      bank.Id,
-								Operation: enums.BotCommands[enums.EXPENSE],
-							},
-						},
-					)
+					bot.ReplyKeyboard.Destroy()
+
+					return
+				}
+
+				remaining := strconv.Itoa(debt.Amount - debt.Repaid)
+
+				bot.ReplyKeyboard.Create([]string{remaining})
+
+				if err = bot.SendMessage(
+					update.Message.Chat.ChatId,
+					"Сколько вернули? Осталось "+remaining+" руб.",
+				); err != nil {
+					log.Fatal(err)
 				}
+
+				bot.ReplyKeyboard.Destroy()
+				processing.Create(
+					update.Message.Chat.ChatId,
+					update.Message.From.UserId,
+					models.Command{
+						Name: enums.REPAY_DEBT,
+						Step: 1,
+					},
+					models.Extra{
+						Debt:     debt,
+						Keyboard: []string{remaining},
+					},
+				)
 			} else if process.Command.Step == 1 {
-				amount, err := strconv.Atoi(update.Message.Text)
+				amount, _, err := utils.ParseAmount(update.Message.Text)
+				if err == nil && amount > process.Extra.Debt.Amount-process.Extra.Debt.Repaid {
+					err = errors.New(enums.UserErrors[enums.DEBT_OVERPAID])
+				}
+
 				if err != nil {
 					log.Println(err)
 
-					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.INCORRECT_VALUE])
+					bot.ReplyKeyboard.Create(process.Extra.Keyboard)
+
+					err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
 					if err != nil {
 						log.Fatal(err)
 					}
-				} else {
-					if err = bot.SendMessage(
-						update.Message.Chat.ChatId,
-						"Добавь комментарий к операции",
-					); err != nil {
-						log.Fatal(err)
-					}
 
-					processing.Create(
-						update.Message.Chat.ChatId,
-						models.Command{
-							Name: enums.EXPENSE,
-							Step: 2,
-						},
-						models.Extra{
-							Bank: process.Extra.Bank,
-							Operation: models.Operation{
-								Account:   process.Extra.Operation.Account,
-								Bank:      process.Extra.Operation.Bank,
-								Operation: process.Extra.Operation.Operation,
-								Amount:    amount,
-							},
-						},
-					)
+					bot.ReplyKeyboard.Destroy()
+
+					return
 				}
-			} else if process.Command.Step == 2 {
-				err := process.Extra.Operation.Create(ctx, &db)
+
+				bankNames, err := utils.GetBankNames(ctx, &db, update.Message.Chat.ChatId)
 				if err != nil {
 					log.Println(err)
+				}
 
-					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
-					if err != nil {
-						log.Fatal(err)
-					}
-				} else {
-					balance := process.Extra.Bank.Balance - process.Extra.Operation.Amount
+				keyboard := append(bankNames, "Без копилки")
 
-					err := process.Extra.Bank.Update(ctx, &db, bson.M{"balance": balance})
-					if err != nil {
-						log.Println(err)
+				text := "В какую копилку положить возвращённые деньги?"
+				if process.Extra.Debt.Direction == enums.DebtDirections[enums.BORROWED] {
+					text = "Из какой копилки ты возвращаешь деньги?"
+				}
 
-						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
-						if err != nil {
-							log.Fatal(err)
-						}
+				bot.ReplyKeyboard.Create(keyboard)
+
+				if err = bot.SendMessage(update.Message.Chat.ChatId, text); err != nil {
+					log.Fatal(err)
+				}
+
+				bot.ReplyKeyboard.Destroy()
+				processing.Create(
+					update.Message.Chat.ChatId,
+					update.Message.From.UserId,
+					models.Command{
+						Name: enums.REPAY_DEBT,
+						Step: 2,
+					},
+					models.Extra{
+						Debt:     process.Extra.Debt,
+						Amount:   amount,
+						Keyboard: keyboard,
+					},
+				)
+			} else if process.Command.Step == 2 {
+				var bank *models.Bank
+				var err error
+
+				if update.Message.Text != "Без копилки" {
+					if bank, err = utils.GetBank(ctx, &db, update.Message.Chat.ChatId, update.Message.Text); err == nil {
+						err = utils.CheckRole(bank, update.Message.Chat.ChatId, enums.CONTRIBUTOR)
 					}
 
-					if err = bot.SendMessage(
-						update.Message.Chat.ChatId,
-						"Баланс копилки был успешно изменен! Текущий баланс: "+strconv.Itoa(balance)+" руб.",
-					); err != nil {
-						log.Fatal(err)
+					if err == nil && process.Extra.Debt.Direction == enums.DebtDirections[enums.BORROWED] &&
+						bank.Overdraft == enums.OverdraftPolicies[enums.FORBID] && bank.Balance < process.Extra.Amount {
+						err = errors.New(enums.UserErrors[enums.INSUFFICIENT_FUNDS])
 					}
-				}
 
-				processing.Destroy(update.Message.Chat.ChatId)
-			}
-			// -------------------------------------------------------------------------------------------------
-		} else if process.Command.Name == enums.CREATE_TRANSFER {
-			// ----------------------------------------------- handle update in /create_transfer command handler
-			if process.Command.Step == 0 {
-				if bank, err := utils.GetBank(ctx, &db, update.Message.Chat.ChatId, update.Message.Text); err != nil {
-					log.Println(err)
+					if err != nil && utils.IsUserError(err) {
+						log.Println(err)
 
-					if err.Error() == enums.UserErrors[enums.BANK_NOT_FOUND] {
 						bot.ReplyKeyboard.Create(process.Extra.Keyboard)
 
 						err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
@@ -580,98 +5527,150 @@ func handler(update models.Update) {
 						}
 
 						bot.ReplyKeyboard.Destroy()
-					} else {
-						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
-						if err != nil {
-							log.Fatal(err)
-						}
 
-						processing.Destroy(update.Message.Chat.ChatId)
-					}
-				} else {
-					err = bot.SendMessage(update.Message.Chat.ChatId, "Какую сумму?")
-					if err != nil {
-						log.Fatal(err)
+						return
 					}
+				}
 
-					processing.Create(
-						update.Message.Chat.ChatId,
-						models.Command{
-							Name: enums.CREATE_TRANSFER,
-							Step: 1,
-						},
-						models.Extra{
-							Bank: bank,
-							Operation: models.Operation{
-								Account:   update.Message.Chat.ChatId,
-								Bank:      bank.Id,
-								Operation: enums.BotCommands[enums.EXPENSE],
-							},
-							Keyboard: process.Extra.Keyboard,
-						},
-					)
+				if err == nil {
+					err = utils.RepayDebt(ctx, &db, process.Extra.Debt, bank, process.Extra.Amount, &update.Message.From)
 				}
-			} else if process.Command.Step == 1 {
-				amount, err := strconv.Atoi(update.Message.Text)
+
 				if err != nil {
 					log.Println(err)
 
-					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.INCORRECT_VALUE])
+					if utils.IsUserError(err) {
+						err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
+					} else {
+						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+					}
 					if err != nil {
 						log.Fatal(err)
 					}
 				} else {
-					bot.ReplyKeyboard.Create(process.Extra.Keyboard)
-
-					if err = bot.SendMessage(
-						update.Message.Chat.ChatId,
-						"В какую копилку?",
-					); err != nil {
-						log.Fatal(err)
+					text := "Возврат долга записан!%0A%0A" + utils.GetDebtDescription(ctx, &db, process.Extra.Debt)
+					if process.Extra.Debt.Closed {
+						text = "Долг полностью возвращён!"
 					}
 
-					bot.ReplyKeyboard.Destroy()
-					processing.Create(
-						update.Message.Chat.ChatId,
-						models.Command{
-							Name: enums.CREATE_TRANSFER,
-							Step: 2,
-						},
-						models.Extra{
-							Bank: process.Extra.Bank,
-							Operation: models.Operation{
-								Account:   process.Extra.Operation.Account,
-								Bank:      process.Extra.Operation.Bank,
-								Operation: process.Extra.Operation.Operation,
-								Amount:    amount,
-							},
-						},
-					)
-				}
-			} else if process.Command.Step == 2 {
-				bankForIncome, err := utils.GetBank(ctx, &db, update.Message.Chat.ChatId, update.Message.Text)
+					if bank != nil {
+						text += "%0A%0AБаланс копилки " + bank.Name + " составляет " + strconv.Itoa(bank.Balance) + " руб."
+					}
 
-				expense := models.Operation{
-					Account:   process.Extra.Operation.Account,
-					Bank:      process.Extra.Operation.Bank,
-					Operation: process.Extra.Operation.Operation,
-					Amount:    process.Extra.Operation.Amount,
-					Comment:   "Перевод в копилку " + bankForIncome.Name,
+					if err = bot.SendMessage(update.Message.Chat.ChatId, text); err != nil {
+						log.Fatal(err)
+					}
 				}
 
-				err = expense.Create(ctx, &db)
+				processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+			}
+			// -------------------------------------------------------------------------------------------------
+		} else if process.Command.Name == enums.SPLIT {
+			// ------------------------------------------------------ handle update in /split command processing
+			if process.Command.Step == 0 {
+				amount, _, err := utils.ParseAmount(update.Message.Text)
 				if err != nil {
 					log.Println(err)
 
-					err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
+					err = bot.SendMessage(update.Message.Chat.ChatId, err.Error())
 					if err != nil {
 						log.Fatal(err)
 					}
+
+					return
+				}
+
+				process.Extra.Split.Amount = amount
+
+				if err = bot.SendMessage(update.Message.Chat.ChatId, "За что?"); err != nil {
+					log.Fatal(err)
+				}
+
+				processing.Create(
+					update.Message.Chat.ChatId,
+					update.Message.From.UserId,
+					models.Command{
+						Name: enums.SPLIT,
+						Step: 1,
+					},
+					process.Extra,
+				)
+			} else if process.Command.Step == 1 {
+				process.Extra.Split.Comment = update.Message.Text
+
+				// everyone who has already paid or shared an expense in this chat is offered on the keyboard,
+				// and so are the administrators of the group, as the bot can't list the other members
+				members := []models.Participant{process.Extra.Split.Payer}
+				known := map[string]bool{process.Extra.Split.Payer.Key(): true}
+
+				if balances, err := utils.GetSplitBalances(ctx, &db, update.Message.Chat.ChatId); err != nil {
+					log.Println(err)
+				} else {
+					for _, balance := range balances {
+						if !known[balance.Participant.Key()] {
+							known[balance.Participant.Key()] = true
+							members = append(members, balance.Participant)
+						}
+					}
+				}
+
+				if administrators, err := bot.GetChatAdministrators(update.Message.Chat.ChatId); err != nil {
+					log.Println(err)
 				} else {
-					balance := process.Extra.Bank.Balance - expense.Amount
+					for _, administrator := range administrators {
+						participant := models.Participant{
+							User:     administrator.UserId,
+							Username: administrator.Username,
+							Name:     administrator.Name(),
+						}
 
-					err := process.Extra.Bank.Update(ctx, &db, bson.M{"balance": balance})
-					if err != nil {
+						if !administrator.IsBot && !known[participant.Key()] {
+							known[participant.Key()] = true
+							members = append(members, participant)
+						}
+					}
+				}
+
+				labels := utils.GetParticipantLabels(members)
+
+				var keyboard []string
+				for _, member := range members {
+					keyboard = append(keyboard, labels[member.Key()])
+				}
+
+				bot.ReplyKeyboard.Create(append([]string{"Готово", "Все"}, keyboard...))
+
+				if err := bot.SendMessage(
+					update.Message.Chat.ChatId,
+					"Между кем разделить расход? Выбирай участников по одному или напиши имена через запятую, "+
+						"а потом нажми «Готово». «Все» — все участники на клавиатуре: те, кто уже участвовал "+
+						"в общих расходах этого чата, и администраторы группы",
+				); err != nil {
+					log.Fatal(err)
+				}
+
+				bot.ReplyKeyboard.Destroy()
+				processing.Create(
+					update.Message.Chat.ChatId,
+					update.Message.From.UserId,
+					models.Command{
+						Name: enums.SPLIT,
+						Step: 2,
+					},
+					models.Extra{
+						Split:    process.Extra.Split,
+						Keyboard: keyboard,
+						Members:  members,
+					},
+				)
+			} else if process.Command.Step == 2 {
+				if update.Message.Text == "Готово" && len(process.Extra.Participants) > 0 {
+					if err := utils.CreateSplit(
+						ctx,
+						&db,
+						process.Extra.Split,
+						process.Extra.Participants,
+					); err != nil {
 						log.Println(err)
 
 						err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
@@ -679,51 +5678,103 @@ func handler(update models.Update) {
 							log.Fatal(err)
 						}
 					} else {
-						income := models.Operation{
-							Account:   process.Extra.Operation.Account,
-							Bank:      bankForIncome.Id,
-							Operation: enums.BotCommands[enums.INCOME],
-							Amount:    process.Extra.Operation.Amount,
-							Comment:   "Перевод из копилки " + process.Extra.Bank.Name,
-						}
+						text := "Расход " + strconv.Itoa(process.Extra.Split.Amount) + " руб. разделён между " +
+							strconv.Itoa(len(process.Extra.Participants)) + " участниками!"
 
-						err = income.Create(ctx, &db)
-						if err != nil {
+						if balances, err := utils.GetSplitBalances(ctx, &db, update.Message.Chat.ChatId); err != nil {
 							log.Println(err)
+						} else {
+							text += "%0A%0AБалансы участников:" + utils.GetSplitSummary(balances) +
+								"%0A%0AЧтобы узнать, кто кому сколько должен перевести, напиши /settle"
+						}
 
-							err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
-							if err != nil {
-								log.Fatal(err)
+						if err = bot.SendMessage(update.Message.Chat.ChatId, text); err != nil {
+							log.Fatal(err)
+						}
+					}
+
+					processing.Destroy(update.Message.Chat.ChatId, update.Message.From.UserId)
+
+					return
+				}
+
+				chosen := process.Extra.Members
+				ambiguous := false
+
+				if update.Message.Text != "Все" {
+					chosen = nil
+
+					for _, name := range strings.Split(update.Message.Text, ",") {
+						name = strings.TrimSpace(name)
+						if name == "" || name == "Готово" {
+							continue
+						}
+
+						// a typed name is matched with a button or, when only one member has it, with a member's name
+						// regardless of the case, and any other name stands for a person outside the group
+						var matches []models.Participant
+						for index, member := range process.Extra.Members {
+							if strings.EqualFold(process.Extra.Keyboard[index], name) {
+								matches = []models.Participant{member}
+
+								break
 							}
-						} else {
-							balance = bankForIncome.Balance + income.Amount
 
-							err := bankForIncome.Update(ctx, &db, bson.M{"balance": balance})
-							if err != nil {
-								log.Println(err)
-
-								err = bot.SendMessage(update.Message.Chat.ChatId, enums.UserErrors[enums.UNEXPECTED_ERROR])
-								if err != nil {
-									log.Fatal(err)
-								}
-							} else {
-								if err = bot.SendMessage(
-									update.Message.Chat.ChatId,
-									"Из копилки "+process.Extra.Bank.Name+" в копилку "+bankForIncome.Name+
-										" было успешно переведено "+strconv.Itoa(income.Amount)+" руб.%0A%0A"+
-										"Баланс копилки "+process.Extra.Bank.Name+
-										" составляет "+strconv.Itoa(process.Extra.Bank.Balance)+" руб.%0A"+
-										"Баланс копилки "+bankForIncome.Name+
-										" составляет "+strconv.Itoa(bankForIncome.Balance)+" руб.%0A",
-								); err != nil {
-									log.Fatal(err)
-								}
+							if strings.EqualFold(member.Name, name) {
+								matches = append(matches, member)
 							}
 						}
+
+						if len(matches) > 1 {
+							ambiguous = true
+						} else if len(matches) == 1 {
+							chosen = append(chosen, matches[0])
+						} else {
+							chosen = append(chosen, models.Participant{Name: name})
+						}
 					}
 				}
 
-				processing.Destroy(update.Message.Chat.ChatId)
+				for _, participant := range chosen {
+					added := false
+					for _, other := range process.Extra.Participants {
+						if other.Key() == participant.Key() {
+							added = true
+						}
+					}
+
+					if !added {
+						process.Extra.Participants = append(process.Extra.Participants, participant)
+					}
+				}
+
+				text := "Выбери хотя бы одного участника"
+				if ambiguous {
+					text = enums.UserErrors[enums.AMBIGUOUS_PARTICIPANT]
+				} else if len(process.Extra.Participants) > 0 {
+					var participants []models.Participant
+					participants = append(participants, process.Extra.Members...)
+					participants = append(participants, process.Extra.Participants...)
+
+					labels := utils.GetParticipantLabels(participants)
+
+					var names []string
+					for _, participant := range process.Extra.Participants {
+						names = append(names, labels[participant.Key()])
+					}
+
+					text = url.QueryEscape("Участники: "+strings.Join(names, ", ")) +
+						". Добавь ещё или нажми «Готово»"
+				}
+
+				bot.ReplyKeyboard.Create(append([]string{"Готово", "Все"}, process.Extra.Keyboard...))
+
+				if err := bot.SendMessage(update.Message.Chat.ChatId, text); err != nil {
+					log.Fatal(err)
+				}
+
+				bot.ReplyKeyboard.Destroy()
+				processing.Create(update.Message.Chat.ChatId, update.Message.From.UserId, process.Command, process.Extra)
 			}
 			// -------------------------------------------------------------------------------------------------
 		}
@@ -10,6 +10,10 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+	_ "time/tzdata"
 
 	"github.com/joho/godotenv"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -20,6 +24,10 @@ var ctx = context.TODO()
 var db models.DataBase
 var bot models.Bot
 var processing models.Processing
+var undoWindow = 15 * time.Minute
+
+// state serializes updates and scheduled jobs: they share the bot keyboards and the reply target, and a dialog step reads and then replaces its dialog
+var state sync.Mutex
 
 func init() {
 	// init .env
@@ -45,20 +53,35 @@ func init() {
 	dbName := os.Getenv("DB_NAME")
 	db.Collections["banks"] = client.Database(dbName).Collection("banks")
 	db.Collections["operations"] = client.Database(dbName).Collection("operations")
+	db.Collections["schedules"] = client.Database(dbName).Collection("schedules")
+	db.Collections["settings"] = client.Database(dbName).Collection("settings")
+	db.Collections["debts"] = client.Database(dbName).Collection("debts")
+	db.Collections["splits"] = client.Database(dbName).Collection("splits")
+
+	if minutes, err := strconv.Atoi(os.Getenv("UNDO_WINDOW")); err == nil {
+		undoWindow = time.Duration(minutes) * time.Minute
+	}
 
 	// init Bot
 	bot.Token = os.Getenv("BOT_TOKEN")
+	bot.Username = os.Getenv("BOT_USERNAME")
 	bot.ReplyKeyboard = models.ReplyKeyboard{
 		Keyboard:       [][]string{},
 		Resize:         true,
 		OneTime:        true,
 		RemoveKeyboard: true,
+		Selective:      true,
+	}
+	bot.InlineKeyboard = models.InlineKeyboard{
+		Keyboard: [][]models.InlineKeyboardButton{},
 	}
 
 	fmt.Println("Инициализация прошла успешно! Бот готов к работе.")
 }
 
 func main() {
+	go scheduler()
+
 	http.HandleFunc("/"+bot.Token, func(rw http.ResponseWriter, r *http.Request) {
 		body, err := ioutil.ReadAll(r.Body)
 		if err != nil {
@@ -72,6 +95,9 @@ func main() {
 			log.Println(err)
 		}
 
+		state.Lock()
+		defer state.Unlock()
+
 		handler(update)
 	})
 
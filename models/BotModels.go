@@ -1,27 +1,47 @@
 package models
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"mime/multipart"
 	"net/http"
+	"net/url"
 	"strconv"
 )
 
 // ---------------------------------------------------------------------------
 // ---------------------------------------------------------------- BOT MODELS
 type Bot struct {
-	Token         string
-	ReplyKeyboard ReplyKeyboard
+	Token          string
+	Username       string
+	ReplyTo        int
+	ReplyKeyboard  ReplyKeyboard
+	InlineKeyboard InlineKeyboard
 }
 
 func (bot *Bot) SendMessage(chat int, text string) error {
 	options := "?chat_id=" + strconv.Itoa(chat) + "&text=" + text
 
-	keyboardJSON, err := json.Marshal(bot.ReplyKeyboard)
+	if bot.ReplyTo != 0 {
+		options += "&reply_to_message_id=" + strconv.Itoa(bot.ReplyTo) + "&allow_sending_without_reply=true"
+	}
+
+	var keyboardJSON []byte
+	var err error
+
+	if len(bot.InlineKeyboard.Keyboard) > 0 {
+		keyboardJSON, err = json.Marshal(bot.InlineKeyboard)
+	} else {
+		keyboardJSON, err = json.Marshal(bot.ReplyKeyboard)
+	}
 	if err != nil {
 		return err
 	}
 
-	options += "&reply_markup=" + string(keyboardJSON)
+	// buttons carry bank names and comments, so the keyboard is escaped like the text
+	options += "&reply_markup=" + url.QueryEscape(string(keyboardJSON))
 
 	resp, err := http.Get("https://api.telegram.org/bot" + bot.Token + "/sendMessage" + options)
 	if err != nil {
@@ -32,29 +52,206 @@ func (bot *Bot) SendMessage(chat int, text string) error {
 	return nil
 }
 
+func (bot *Bot) EditMessage(chat int, message int, text string) error {
+	options := "?chat_id=" + strconv.Itoa(chat) + "&message_id=" + strconv.Itoa(message) + "&text=" + text
+
+	keyboardJSON, err := json.Marshal(bot.InlineKeyboard)
+	if err != nil {
+		return err
+	}
+
+	options += "&reply_markup=" + url.QueryEscape(string(keyboardJSON))
+
+	resp, err := http.Get("https://api.telegram.org/bot" + bot.Token + "/editMessageText" + options)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+func (bot *Bot) AnswerCallbackQuery(callbackQuery string, text string) error {
+	options := "?callback_query_id=" + callbackQuery + "&text=" + text
+
+	resp, err := http.Get("https://api.telegram.org/bot" + bot.Token + "/answerCallbackQuery" + options)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+func (bot *Bot) SendDocument(chat int, name string, data []byte) error {
+	return bot.sendFile("sendDocument", "document", chat, name, data)
+}
+
+func (bot *Bot) SendPhoto(chat int, name string, data []byte) error {
+	return bot.sendFile("sendPhoto", "photo", chat, name, data)
+}
+
+func (bot *Bot) sendFile(method string, field string, chat int, name string, data []byte) error {
+	var body bytes.Buffer
+
+	writer := multipart.NewWriter(&body)
+
+	if err := writer.WriteField("chat_id", strconv.Itoa(chat)); err != nil {
+		return err
+	}
+
+	file, err := writer.CreateFormFile(field, name)
+	if err != nil {
+		return err
+	}
+
+	if _, err = file.Write(data); err != nil {
+		return err
+	}
+
+	if err = writer.Close(); err != nil {
+		return err
+	}
+
+	resp, err := http.Post(
+		"https://api.telegram.org/bot"+bot.Token+"/"+method,
+		writer.FormDataContentType(),
+		&body,
+	)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+func (bot *Bot) GetFile(file string) ([]byte, error) {
+	resp, err := http.Get("https://api.telegram.org/bot" + bot.Token + "/getFile?file_id=" + file)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Ok     bool `json:"ok"`
+		Result struct {
+			FilePath string `json:"file_path"`
+		} `json:"result"`
+	}
+
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	if !result.Ok {
+		return nil, errors.New("telegram: file " + file + " is not available")
+	}
+
+	resp, err = http.Get("https://api.telegram.org/file/bot" + bot.Token + "/" + result.Result.FilePath)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+func (bot *Bot) GetChatAdministrators(chat int) ([]User, error) {
+	resp, err := http.Get("https://api.telegram.org/bot" + bot.Token + "/getChatAdministrators?chat_id=" + strconv.Itoa(chat))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Ok     bool `json:"ok"`
+		Result []struct {
+			User User `json:"user"`
+		} `json:"result"`
+	}
+
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	if !result.Ok {
+		return nil, errors.New("telegram: administrators of chat " + strconv.Itoa(chat) + " are not available")
+	}
+
+	var administrators []User
+	for _, member := range result.Result {
+		administrators = append(administrators, member.User)
+	}
+
+	return administrators, nil
+}
+
 // Updates Models ------------------------------------------------------------
 type Update struct {
-	UpdateId int     `json:"update_id"`
-	Message  Message `json:"message"`
+	UpdateId      int           `json:"update_id"`
+	Message       Message       `json:"message"`
+	CallbackQuery CallbackQuery `json:"callback_query"`
 }
 
 type Message struct {
-	MessagId int    `json:"message_id"`
-	Chat     Chat   `json:"chat"`
-	Text     string `json:"text"`
+	MessagId       int         `json:"message_id"`
+	From           User        `json:"from"`
+	Chat           Chat        `json:"chat"`
+	Text           string      `json:"text"`
+	Caption        string      `json:"caption"`
+	Photo          []PhotoSize `json:"photo"`
+	Document       Document    `json:"document"`
+	ReplyToMessage *Message    `json:"reply_to_message"`
+}
+
+type Document struct {
+	FileId   string `json:"file_id"`
+	FileName string `json:"file_name"`
+	MimeType string `json:"mime_type"`
+}
+
+type PhotoSize struct {
+	FileId string `json:"file_id"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
 }
 
 type Chat struct {
 	ChatId   int    `json:"id"`
+	Type     string `json:"type"`
 	Username string `json:"username"`
 }
 
+type User struct {
+	UserId    int    `json:"id" bson:"id"`
+	IsBot     bool   `json:"is_bot" bson:"-"`
+	Username  string `json:"username" bson:"username"`
+	FirstName string `json:"first_name" bson:"first_name"`
+}
+
+func (user *User) Name() string {
+	if user.FirstName != "" {
+		return user.FirstName
+	}
+
+	return user.Username
+}
+
+type CallbackQuery struct {
+	CallbackQueryId string  `json:"id"`
+	From            User    `json:"from"`
+	Message         Message `json:"message"`
+	Data            string  `json:"data"`
+}
+
 // ReplyKeyboard Models ------------------------------------------------------
 type ReplyKeyboard struct {
 	Keyboard       [][]string `json:"keyboard"`
 	Resize         bool       `json:"resize_keyboard"`
 	OneTime        bool       `json:"one_time_keyboard"`
 	RemoveKeyboard bool       `json:"remove_keyboard"`
+	Selective      bool       `json:"selective"`
 }
 
 func (rk *ReplyKeyboard) Create(buttons []string) {
@@ -80,3 +277,23 @@ func (rk *ReplyKeyboard) Destroy() {
 	rk.Keyboard = [][]string{}
 	rk.RemoveKeyboard = true
 }
+
+// InlineKeyboard Models -----------------------------------------------------
+type InlineKeyboard struct {
+	Keyboard [][]InlineKeyboardButton `json:"inline_keyboard"`
+}
+
+type InlineKeyboardButton struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data"`
+}
+
+func (ik *InlineKeyboard) Create(buttons []InlineKeyboardButton) {
+	if len(buttons) > 0 {
+		ik.Keyboard = append(ik.Keyboard, buttons)
+	}
+}
+
+func (ik *InlineKeyboard) Destroy() {
+	ik.Keyboard = [][]InlineKeyboardButton{}
+}
@@ -0,0 +1,14 @@
+package models
+
+// ---------------------------------------------------------------------------
+// -------------------------------------------------------------- CHART MODELS
+type Chart struct {
+	Title  string
+	Labels []string
+	Series []Series
+}
+
+type Series struct {
+	Name   string
+	Values []int
+}
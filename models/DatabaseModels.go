@@ -2,6 +2,8 @@ package models
 
 import (
 	"context"
+	"strconv"
+	"strings"
 	"time"
 
 	gonanoid "github.com/matoous/go-nanoid/v2"
@@ -16,8 +18,17 @@ type DataBase struct {
 	Collections map[string]*mongo.Collection
 }
 
-func (db *DataBase) GetDocuments(ctx context.Context, collection string, filter bson.M) (*mongo.Cursor, error) {
-	documents, err := db.Collections[collection].Find(ctx, filter)
+func (db *DataBase) GetDocuments(ctx context.Context, collection string, filter bson.M, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	documents, err := db.Collections[collection].Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return documents, nil
+}
+
+func (db *DataBase) Aggregate(ctx context.Context, collection string, pipeline []bson.M) (*mongo.Cursor, error) {
+	documents, err := db.Collections[collection].Aggregate(ctx, pipeline)
 	if err != nil {
 		return nil, err
 	}
@@ -31,12 +42,32 @@ func (db *DataBase) GetDocument(ctx context.Context, collection string, filter b
 
 // Bank Models ---------------------------------------------------------------
 type Bank struct {
-	Id        string `json:"id" bson:"id"`
-	Account   int    `json:"account" bson:"account"`
-	Name      string `json:"name" bson:"name"`
-	Balance   int    `json:"balance" bson:"balance"`
-	CreatedAt string `json:"created_at" bson:"created_at"`
-	UpdatedAt string `json:"updated_at" bson:"updated_at"`
+	Id        string    `json:"id" bson:"id"`
+	Account   int       `json:"account" bson:"account"`
+	Name      string    `json:"name" bson:"name"`
+	Balance   int       `json:"balance" bson:"balance"`
+	Goal      int       `json:"goal" bson:"goal"`
+	Deadline  time.Time `json:"deadline" bson:"deadline"`
+	Limit     int       `json:"limit" bson:"limit"`
+	Period    string    `json:"period" bson:"period"`
+	Overdraft string    `json:"overdraft" bson:"overdraft"`
+	Backup    string    `json:"backup" bson:"backup"`
+	Parent    string    `json:"parent" bson:"parent"`
+	Members   []Member  `json:"members,omitempty" bson:"members,omitempty"`
+	Invites   []Invite  `json:"invites,omitempty" bson:"invites,omitempty"`
+	CreatedAt string    `json:"created_at" bson:"created_at"`
+	UpdatedAt string    `json:"updated_at" bson:"updated_at"`
+}
+
+type Member struct {
+	Account int    `json:"account" bson:"account"`
+	Name    string `json:"name" bson:"name"`
+	Role    string `json:"role" bson:"role"`
+}
+
+type Invite struct {
+	Code string `json:"code" bson:"code"`
+	Role string `json:"role" bson:"role"`
 }
 
 func (bank *Bank) Create(ctx context.Context, db *DataBase) error {
@@ -97,15 +128,61 @@ func (bank *Bank) Update(ctx context.Context, db *DataBase, update bson.M) error
 	return nil
 }
 
+func (bank *Bank) Increase(ctx context.Context, db *DataBase, amount int) error {
+	after := options.After
+	options := &options.FindOneAndUpdateOptions{ReturnDocument: &after}
+	err := db.Collections["banks"].FindOneAndUpdate(
+		ctx,
+		bson.M{
+			"account": bank.Account,
+			"id":      bank.Id,
+		},
+		bson.M{
+			"$inc": bson.M{"balance": amount},
+			"$set": bson.M{"updated_at": time.Now().String()},
+		},
+		options,
+	).Decode(&bank)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // Operation Models ----------------------------------------------------------
 type Operation struct {
-	Id        string `json:"id" bson:"id"`
-	Account   int    `json:"account" bson:"account"`
-	Bank      string `json:"bank" bson:"bank"`
-	Operation string `json:"operation" bson:"operation"`
-	Amount    int    `json:"amount" bson:"amount"`
-	Comment   string `json:"comment" bson:"comment"`
-	CreatedAt string `json:"created_at" bson:"created_at"`
+	Id        string   `json:"id" bson:"id"`
+	Account   int      `json:"account" bson:"account"`
+	Bank      string   `json:"bank" bson:"bank"`
+	Operation string   `json:"operation" bson:"operation"`
+	Amount    int      `json:"amount" bson:"amount"`
+	Comment   string   `json:"comment" bson:"comment"`
+	Transfer  string   `json:"transfer,omitempty" bson:"transfer,omitempty"`
+	Schedule  string   `json:"schedule,omitempty" bson:"schedule,omitempty"`
+	Reverts   string   `json:"reverts,omitempty" bson:"reverts,omitempty"`
+	Reverted  bool     `json:"reverted,omitempty" bson:"reverted,omitempty"`
+	Edits     []Edit   `json:"edits,omitempty" bson:"edits,omitempty"`
+	Receipt   *Receipt `json:"receipt,omitempty" bson:"receipt,omitempty"`
+	Import    string   `json:"import,omitempty" bson:"import,omitempty"`
+	Author    *User    `json:"author,omitempty" bson:"author,omitempty"`
+	Pending   bool     `json:"pending,omitempty" bson:"pending,omitempty"`
+	Debt      string   `json:"debt,omitempty" bson:"debt,omitempty"`
+	Cover     string   `json:"cover,omitempty" bson:"cover,omitempty"`
+	CreatedAt string   `json:"created_at" bson:"created_at"`
+}
+
+type Edit struct {
+	Field    string `json:"field" bson:"field"`
+	Old      string `json:"old" bson:"old"`
+	New      string `json:"new" bson:"new"`
+	EditedAt string `json:"edited_at" bson:"edited_at"`
+}
+
+type Receipt struct {
+	FN string `json:"fn" bson:"fn"`
+	FD string `json:"fd" bson:"fd"`
+	FP string `json:"fp" bson:"fp"`
 }
 
 func (operation *Operation) Create(ctx context.Context, db *DataBase) error {
@@ -116,7 +193,9 @@ func (operation *Operation) Create(ctx context.Context, db *DataBase) error {
 
 	operation.Id = id
 
-	operation.CreatedAt = time.Now().String()
+	if operation.CreatedAt == "" {
+		operation.CreatedAt = time.Now().String()
+	}
 
 	_, err = db.Collections["operations"].InsertOne(ctx, operation)
 	if err != nil {
@@ -125,3 +204,259 @@ func (operation *Operation) Create(ctx context.Context, db *DataBase) error {
 
 	return nil
 }
+
+func (operation *Operation) Destroy(ctx context.Context, db *DataBase) error {
+	if _, err := db.Collections["operations"].DeleteOne(
+		ctx,
+		bson.M{
+			"account": operation.Account,
+			"id":      operation.Id,
+		},
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (operation *Operation) Update(ctx context.Context, db *DataBase, update bson.M) error {
+	after := options.After
+	options := &options.FindOneAndUpdateOptions{ReturnDocument: &after}
+	err := db.Collections["operations"].FindOneAndUpdate(
+		ctx,
+		bson.M{
+			"account": operation.Account,
+			"id":      operation.Id,
+		},
+		bson.M{
+			"$set": update,
+		},
+		options,
+	).Decode(&operation)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Schedule Models -----------------------------------------------------------
+type Schedule struct {
+	Id        string    `json:"id" bson:"id"`
+	Account   int       `json:"account" bson:"account"`
+	Operation string    `json:"operation" bson:"operation"`
+	Bank      string    `json:"bank" bson:"bank"`
+	Target    string    `json:"target" bson:"target"`
+	Amount    int       `json:"amount" bson:"amount"`
+	Comment   string    `json:"comment" bson:"comment"`
+	Rule      string    `json:"rule" bson:"rule"`
+	Cron      string    `json:"cron" bson:"cron"`
+	NextRun   time.Time `json:"next_run" bson:"next_run"`
+	Paused    bool      `json:"paused" bson:"paused"`
+	CreatedAt string    `json:"created_at" bson:"created_at"`
+	UpdatedAt string    `json:"updated_at" bson:"updated_at"`
+}
+
+func (schedule *Schedule) Create(ctx context.Context, db *DataBase) error {
+	id, err := gonanoid.New()
+	if err != nil {
+		return err
+	}
+
+	schedule.Id = id
+
+	schedule.CreatedAt = time.Now().String()
+	schedule.UpdatedAt = time.Now().String()
+
+	_, err = db.Collections["schedules"].InsertOne(ctx, schedule)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (schedule *Schedule) Destroy(ctx context.Context, db *DataBase) error {
+	if _, err := db.Collections["schedules"].DeleteOne(
+		ctx,
+		bson.M{
+			"account": schedule.Account,
+			"id":      schedule.Id,
+		},
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (schedule *Schedule) Update(ctx context.Context, db *DataBase, update bson.M) error {
+	update["updated_at"] = time.Now().String()
+
+	after := options.After
+	options := &options.FindOneAndUpdateOptions{ReturnDocument: &after}
+	err := db.Collections["schedules"].FindOneAndUpdate(
+		ctx,
+		bson.M{
+			"account": schedule.Account,
+			"id":      schedule.Id,
+		},
+		bson.M{
+			"$set": update,
+		},
+		options,
+	).Decode(&schedule)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Debt Models ---------------------------------------------------------------
+type Debt struct {
+	Id        string    `json:"id" bson:"id"`
+	Account   int       `json:"account" bson:"account"`
+	Direction string    `json:"direction" bson:"direction"`
+	Person    string    `json:"person" bson:"person"`
+	Amount    int       `json:"amount" bson:"amount"`
+	Repaid    int       `json:"repaid" bson:"repaid"`
+	Bank      string    `json:"bank,omitempty" bson:"bank,omitempty"`
+	Due       time.Time `json:"due" bson:"due"`
+	Reminded  bool      `json:"reminded" bson:"reminded"`
+	Closed    bool      `json:"closed" bson:"closed"`
+	CreatedAt string    `json:"created_at" bson:"created_at"`
+	UpdatedAt string    `json:"updated_at" bson:"updated_at"`
+}
+
+func (debt *Debt) Create(ctx context.Context, db *DataBase) error {
+	id, err := gonanoid.New()
+	if err != nil {
+		return err
+	}
+
+	debt.Id = id
+
+	debt.CreatedAt = time.Now().String()
+	debt.UpdatedAt = time.Now().String()
+
+	_, err = db.Collections["debts"].InsertOne(ctx, debt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (debt *Debt) Update(ctx context.Context, db *DataBase, update bson.M) error {
+	update["updated_at"] = time.Now().String()
+
+	after := options.After
+	options := &options.FindOneAndUpdateOptions{ReturnDocument: &after}
+	err := db.Collections["debts"].FindOneAndUpdate(
+		ctx,
+		bson.M{
+			"account": debt.Account,
+			"id":      debt.Id,
+		},
+		bson.M{
+			"$set": update,
+		},
+		options,
+	).Decode(&debt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Split Models --------------------------------------------------------------
+type Split struct {
+	Id         string      `json:"id" bson:"id"`
+	Account    int         `json:"account" bson:"account"`
+	Payer      Participant `json:"payer" bson:"payer"`
+	Amount     int         `json:"amount" bson:"amount"`
+	Comment    string      `json:"comment" bson:"comment"`
+	Shares     []Share     `json:"shares" bson:"shares"`
+	Settlement bool        `json:"settlement,omitempty" bson:"settlement,omitempty"`
+	Batch      string      `json:"batch,omitempty" bson:"batch,omitempty"`
+	Proposed   bool        `json:"proposed,omitempty" bson:"proposed,omitempty"`
+	Author     *User       `json:"author,omitempty" bson:"author,omitempty"`
+	CreatedAt  string      `json:"created_at" bson:"created_at"`
+}
+
+type Share struct {
+	Participant Participant `json:"participant" bson:"participant"`
+	Amount      int         `json:"amount" bson:"amount"`
+}
+
+// Participant is a member of the chat or, without a user id, a person outside of it who is known only by name
+type Participant struct {
+	User     int    `json:"user,omitempty" bson:"user,omitempty"`
+	Username string `json:"username,omitempty" bson:"username,omitempty"`
+	Name     string `json:"name" bson:"name"`
+}
+
+// Key tells participants apart by the user id, so members with the same name stay separate and renames change nothing
+func (participant *Participant) Key() string {
+	if participant.User != 0 {
+		return "user:" + strconv.Itoa(participant.User)
+	}
+
+	return "name:" + strings.ToLower(participant.Name)
+}
+
+func (split *Split) Create(ctx context.Context, db *DataBase) error {
+	id, err := gonanoid.New()
+	if err != nil {
+		return err
+	}
+
+	split.Id = id
+	split.CreatedAt = time.Now().String()
+
+	_, err = db.Collections["splits"].InsertOne(ctx, split)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Settings Models -----------------------------------------------------------
+type Settings struct {
+	Account    int       `json:"account" bson:"account"`
+	Digest     string    `json:"digest" bson:"digest"`
+	DigestHour int       `json:"digest_hour" bson:"digest_hour"`
+	Timezone   string    `json:"timezone" bson:"timezone"`
+	QuietFrom  int       `json:"quiet_from" bson:"quiet_from"`
+	QuietTo    int       `json:"quiet_to" bson:"quiet_to"`
+	LastDigest time.Time `json:"last_digest" bson:"last_digest"`
+	Income     string    `json:"income_account" bson:"income_account"`
+	Expense    string    `json:"expense_account" bson:"expense_account"`
+	UpdatedAt  string    `json:"updated_at" bson:"updated_at"`
+}
+
+func (settings *Settings) Update(ctx context.Context, db *DataBase, update bson.M) error {
+	update["updated_at"] = time.Now().String()
+
+	after := options.After
+	upsert := true
+	options := &options.FindOneAndUpdateOptions{ReturnDocument: &after, Upsert: &upsert}
+	err := db.Collections["settings"].FindOneAndUpdate(
+		ctx,
+		bson.M{
+			"account": settings.Account,
+		},
+		bson.M{
+			"$set": update,
+		},
+		options,
+	).Decode(&settings)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
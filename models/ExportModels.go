@@ -0,0 +1,8 @@
+package models
+
+// ---------------------------------------------------------------------------
+// ------------------------------------------------------------- EXPORT MODELS
+type Sheet struct {
+	Name string
+	Rows [][]string
+}
@@ -1,37 +1,63 @@
 package models
 
-import "BIEAS_bot/enums"
+import (
+	"BIEAS_bot/enums"
+	"time"
+)
 
 // ---------------------------------------------------------------------------
 // --------------------------------------------------------- PROCESSING MODELS
+// Processing has no lock of its own: every update and scheduled job runs under the state mutex of main
 type Processing struct {
-	Processes []Process
+	processes []Process
 }
 
-func (processing *Processing) Create(chat int, command Command, extra Extra) {
-	processing.Destroy(chat)
+func (processing *Processing) Create(chat int, user int, command Command, extra Extra) {
+	processing.Destroy(chat, user)
 
-	processing.Processes = append(processing.Processes, Process{
+	processing.processes = append(processing.processes, Process{
 		Chat:    chat,
+		User:    user,
 		Command: command,
 		Extra:   extra,
 	})
 }
 
-func (processing *Processing) Destroy(chat int) {
-	for index, command := range processing.Processes {
-		if command.Chat == chat {
-			processing.Processes[index] = processing.Processes[len(processing.Processes)-1]
-			processing.Processes = processing.Processes[:len(processing.Processes)-1]
+func (processing *Processing) Destroy(chat int, user int) {
+	for index, command := range processing.processes {
+		if command.Chat == chat && command.User == user {
+			processing.processes[index] = processing.processes[len(processing.processes)-1]
+			processing.processes = processing.processes[:len(processing.processes)-1]
 
 			break
 		}
 	}
 }
 
+func (processing *Processing) Get(chat int, user int) Process {
+	for _, command := range processing.processes {
+		if command.Chat == chat && command.User == user {
+			return command
+		}
+	}
+
+	return Process{}
+}
+
+func (processing *Processing) Exists(chat int, user int) bool {
+	for _, command := range processing.processes {
+		if command.Chat == chat && command.User == user {
+			return true
+		}
+	}
+
+	return false
+}
+
 // Process Models ------------------------------------------------------------
 type Process struct {
 	Chat    int
+	User    int
 	Command Command
 	Extra   Extra
 }
@@ -42,7 +68,35 @@ type Command struct {
 }
 
 type Extra struct {
-	Bank      *Bank
-	Operation Operation
-	Keyboard  []string
+	Bank         *Bank
+	Operation    Operation
+	Keyboard     []string
+	Goal         int
+	Schedule     *Schedule
+	Settings     *Settings
+	Filter       Filter
+	Field        string
+	Amount       int
+	Level        string
+	Root         []string
+	Records      [][]string
+	Operations   []Operation
+	Chart        enums.Chart
+	Debt         *Debt
+	Split        *Split
+	Participants []Participant
+	Members      []Participant
+}
+
+type Filter struct {
+	Bank string
+	Type string
+	From time.Time
+	To   time.Time
+}
+
+type Columns struct {
+	Date        int
+	Amount      int
+	Description int
 }
@@ -0,0 +1,20 @@
+package models
+
+// ---------------------------------------------------------------------------
+// ------------------------------------------------------------- REPORT MODELS
+type Report struct {
+	Income  int
+	Expense int
+	Banks   map[string]*Totals
+}
+
+type Totals struct {
+	Income      int
+	Expense     int
+	TransferIn  int
+	TransferOut int
+}
+
+func (totals *Totals) Net() int {
+	return totals.Income + totals.TransferIn - totals.Expense - totals.TransferOut
+}
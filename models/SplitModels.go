@@ -0,0 +1,14 @@
+package models
+
+// ---------------------------------------------------------------------------
+// -------------------------------------------------------------- SPLIT MODELS
+type SplitBalance struct {
+	Participant Participant
+	Balance     int
+}
+
+type Settlement struct {
+	From   Participant
+	To     Participant
+	Amount int
+}
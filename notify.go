@@ -0,0 +1,57 @@
+package main
+
+import (
+	"BIEAS_bot/models"
+	"BIEAS_bot/utils"
+	"log"
+)
+
+// notifyMembers tells everyone who shares the bank, except the account that has changed it, what has happened
+func notifyMembers(bank *models.Bank, account int, text string) {
+	// the notifications go to other chats, so they can't be replies to the current message
+	replyTo := bot.ReplyTo
+	bot.ReplyTo = 0
+	defer func() { bot.ReplyTo = replyTo }()
+
+	recipients := []int{bank.Account}
+	for _, member := range bank.Members {
+		recipients = append(recipients, member.Account)
+	}
+
+	for _, recipient := range recipients {
+		if recipient == account {
+			continue
+		}
+
+		if err := bot.SendMessage(recipient, text); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// requestApproval sends a pending operation to every approver of the bank in private with buttons to approve or reject it
+func requestApproval(bank *models.Bank, operation *models.Operation) {
+	replyTo := bot.ReplyTo
+	bot.ReplyTo = 0
+	defer func() { bot.ReplyTo = replyTo }()
+
+	bot.InlineKeyboard.Create([]models.InlineKeyboardButton{
+		{
+			Text:         "Одобрить",
+			CallbackData: "approve:" + operation.Id,
+		},
+		{
+			Text:         "Отклонить",
+			CallbackData: "reject:" + operation.Id,
+		},
+	})
+	defer bot.InlineKeyboard.Destroy()
+
+	text := "Новый запрос на одобрение%0A%0A" + utils.GetOperationDescription(ctx, &db, operation)
+
+	for _, approver := range getApprovers(bank) {
+		if err := bot.SendMessage(approver, text); err != nil {
+			log.Println(err)
+		}
+	}
+}
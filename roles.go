@@ -0,0 +1,80 @@
+package main
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"BIEAS_bot/utils"
+	"log"
+)
+
+// checkRole lets a member act on a bank by their own role or, in a group chat, by the role of the group
+func checkRole(bank *models.Bank, chat int, user int, role enums.Role) error {
+	if err := utils.CheckRole(bank, user, role); err == nil || chat == user {
+		return err
+	}
+
+	return utils.CheckRole(bank, chat, role)
+}
+
+// checkOperationRole checks the role on the bank of the operation as it is now, not as it was when the operation was made
+func checkOperationRole(operation *models.Operation, chat int, user int, role enums.Role) error {
+	bank, err := utils.GetBankById(ctx, &db, operation.Account, operation.Bank)
+	if err != nil {
+		return err
+	}
+
+	return checkRole(bank, chat, user, role)
+}
+
+// getApprovers returns the users who may approve requests to the bank: its owner and admins, where a group stands for
+// the administrators of that group, as a request must be approved by a person and not by anyone who is in the chat
+func getApprovers(bank *models.Bank) []int {
+	accounts := []int{bank.Account}
+	for _, member := range bank.Members {
+		if utils.GetRole(bank, member.Account) >= enums.ADMIN {
+			accounts = append(accounts, member.Account)
+		}
+	}
+
+	var approvers []int
+	approved := make(map[int]bool)
+
+	for _, account := range accounts {
+		users := []int{account}
+
+		// group chats have negative ids
+		if account < 0 {
+			administrators, err := bot.GetChatAdministrators(account)
+			if err != nil {
+				log.Println(err)
+			}
+
+			users = nil
+			for _, administrator := range administrators {
+				if !administrator.IsBot {
+					users = append(users, administrator.UserId)
+				}
+			}
+		}
+
+		for _, user := range users {
+			if !approved[user] {
+				approved[user] = true
+				approvers = append(approvers, user)
+			}
+		}
+	}
+
+	return approvers
+}
+
+// isApprover checks the user who pressed a button, not the chat where the request was shown
+func isApprover(bank *models.Bank, user int) bool {
+	for _, approver := range getApprovers(bank) {
+		if approver == user {
+			return true
+		}
+	}
+
+	return false
+}
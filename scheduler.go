@@ -0,0 +1,286 @@
+package main
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"BIEAS_bot/utils"
+	"log"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func scheduler() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		state.Lock()
+
+		runSchedules(now)
+		runDigests(now)
+		runDebtReminders(now)
+
+		state.Unlock()
+	}
+}
+
+func runSchedules(now time.Time) {
+	documents, err := db.GetDocuments(ctx, "schedules", bson.M{
+		"paused":   false,
+		"next_run": bson.M{"$lte": now},
+	})
+	if err != nil {
+		log.Println(err)
+
+		return
+	}
+	defer documents.Close(ctx)
+
+	var schedules []models.Schedule
+
+	if err = documents.All(ctx, &schedules); err != nil {
+		log.Println(err)
+
+		return
+	}
+
+	for index := range schedules {
+		schedule := &schedules[index]
+
+		// every occurrence missed while the bot was down is materialized, but not more than a month of them
+		for occurrence := 0; !schedule.NextRun.After(now); occurrence++ {
+			run := schedule.NextRun
+
+			next, err := utils.GetNextRun(schedule.Cron, run)
+			if err != nil || occurrence >= 31 {
+				next, err = utils.GetNextRun(schedule.Cron, now)
+			}
+
+			if err != nil {
+				log.Println(err)
+
+				if err = schedule.Update(ctx, &db, bson.M{"paused": true}); err != nil {
+					log.Println(err)
+				}
+
+				break
+			}
+
+			if err = schedule.Update(ctx, &db, bson.M{"next_run": next}); err != nil {
+				log.Println(err)
+
+				break
+			}
+
+			if occurrence >= 31 {
+				break
+			}
+
+			text, err := runSchedule(schedule)
+			if err != nil {
+				log.Println(err)
+
+				text = "Не удалось выполнить регулярную операцию:%0A" + utils.GetScheduleDescription(ctx, &db, schedule)
+			}
+
+			if err = bot.SendMessage(schedule.Account, text); err != nil {
+				log.Println(err)
+			}
+		}
+	}
+}
+
+func runSchedule(schedule *models.Schedule) (string, error) {
+	bank, err := utils.GetBankById(ctx, &db, schedule.Account, schedule.Bank)
+	if err != nil {
+		return "", err
+	}
+
+	var text, cover string
+
+	if schedule.Operation != enums.BotCommands[enums.INCOME] {
+		backup, shortfall, err := utils.GetOverdraftCover(ctx, &db, bank, schedule.Amount)
+		if err != nil {
+			return "", err
+		}
+
+		if backup != nil {
+			transfer, err := utils.CreateTransfer(ctx, &db, backup, bank, shortfall, nil)
+			if err != nil {
+				return "", err
+			}
+
+			cover = transfer.Transfer
+
+			text += "Недостающие " + strconv.Itoa(shortfall) + " руб. были переведены из копилки " + backup.Name + "%0A%0A"
+		} else if shortfall > 0 && bank.Overdraft == enums.OverdraftPolicies[enums.FORBID] {
+			return "Регулярная операция не выполнена: в копилке " + bank.Name + " недостаточно средств", nil
+		}
+	}
+
+	if schedule.Operation == enums.BotCommands[enums.CREATE_TRANSFER] {
+		target, err := utils.GetBankById(ctx, &db, schedule.Account, schedule.Target)
+		if err != nil {
+			return "", err
+		}
+
+		transfer, err := utils.CreateTransfer(ctx, &db, bank, target, schedule.Amount, nil)
+		if err != nil {
+			return "", err
+		}
+
+		if cover != "" {
+			if err = transfer.Update(ctx, &db, bson.M{"cover": cover}); err != nil {
+				return "", err
+			}
+		}
+
+		return text + "Выполнен регулярный перевод " + strconv.Itoa(schedule.Amount) + " руб. из копилки " +
+			bank.Name + " в копилку " + target.Name + "%0A%0A" +
+			"Баланс копилки " + bank.Name + " составляет " + strconv.Itoa(bank.Balance) + " руб.%0A" +
+			"Баланс копилки " + target.Name + " составляет " + strconv.Itoa(target.Balance) + " руб.", nil
+	}
+
+	operation := models.Operation{
+		Account:   schedule.Account,
+		Operation: schedule.Operation,
+		Amount:    schedule.Amount,
+		Comment:   schedule.Comment,
+		Schedule:  schedule.Id,
+		Cover:     cover,
+	}
+
+	if err = utils.CreateOperation(ctx, &db, bank, &operation); err != nil {
+		return "", err
+	}
+
+	return text + "Выполнена регулярная операция:%0A" + utils.GetScheduleDescription(ctx, &db, schedule) + "%0A%0A" +
+		"Баланс копилки " + bank.Name + " составляет " + strconv.Itoa(bank.Balance) + " руб.", nil
+}
+
+func runDigests(now time.Time) {
+	documents, err := db.GetDocuments(ctx, "settings", bson.M{
+		"digest": bson.M{"$in": []string{enums.Periods[enums.DAY], enums.Periods[enums.WEEK]}},
+	})
+	if err != nil {
+		log.Println(err)
+
+		return
+	}
+	defer documents.Close(ctx)
+
+	var settings []models.Settings
+
+	if err = documents.All(ctx, &settings); err != nil {
+		log.Println(err)
+
+		return
+	}
+
+	for index := range settings {
+		item := &settings[index]
+
+		location, err := utils.GetLocation(item.Timezone)
+		if err != nil {
+			log.Println(err)
+
+			continue
+		}
+
+		local := now.In(location)
+		if local.Hour() < item.DigestHour || isQuietHour(item, local.Hour()) {
+			continue
+		}
+
+		if !item.LastDigest.Before(utils.GetPeriodStart(item.Digest, local)) {
+			continue
+		}
+
+		title := "Сводка за день"
+		from := item.LastDigest
+
+		if item.Digest == enums.Periods[enums.WEEK] {
+			title = "Сводка за неделю"
+
+			if from.IsZero() {
+				from = now.AddDate(0, 0, -7)
+			}
+		} else if from.IsZero() {
+			from = now.AddDate(0, 0, -1)
+		}
+
+		digest, err := utils.GetDigest(ctx, &db, item.Account, from)
+		if err != nil {
+			log.Println(err)
+
+			continue
+		}
+
+		if err = item.Update(ctx, &db, bson.M{"last_digest": now}); err != nil {
+			log.Println(err)
+
+			continue
+		}
+
+		if err = bot.SendMessage(item.Account, title+"%0A%0A"+digest); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+func runDebtReminders(now time.Time) {
+	// the reminder comes once, three days before the due date or at once if the debt is due sooner
+	documents, err := db.GetDocuments(ctx, "debts", bson.M{
+		"closed":   false,
+		"reminded": false,
+		"due": bson.M{
+			"$gt":  time.Time{},
+			"$lte": now.AddDate(0, 0, 3),
+		},
+	})
+	if err != nil {
+		log.Println(err)
+
+		return
+	}
+	defer documents.Close(ctx)
+
+	var debts []models.Debt
+
+	if err = documents.All(ctx, &debts); err != nil {
+		log.Println(err)
+
+		return
+	}
+
+	for index := range debts {
+		debt := &debts[index]
+
+		if err = debt.Update(ctx, &db, bson.M{"reminded": true}); err != nil {
+			log.Println(err)
+
+			continue
+		}
+
+		if err = bot.SendMessage(
+			debt.Account,
+			"Напоминание: скоро срок возврата долга%0A%0A"+utils.GetDebtDescription(ctx, &db, debt),
+		); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+func isQuietHour(settings *models.Settings, hour int) bool {
+	if settings.QuietFrom == settings.QuietTo {
+		return false
+	}
+
+	if settings.QuietFrom < settings.QuietTo {
+		return hour >= settings.QuietFrom && hour < settings.QuietTo
+	}
+
+	return hour >= settings.QuietFrom || hour < settings.QuietTo
+}
@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func AcceptInvite(ctx context.Context, db *models.DataBase, code string, member models.Member) (*models.Bank, error) {
+	var bank *models.Bank
+
+	err := db.GetDocument(ctx, "banks", bson.M{"invites.code": code}).Decode(&bank)
+	if err != nil {
+		if err.Error() == "mongo: no documents in result" {
+			return nil, errors.New(enums.UserErrors[enums.INVITE_NOT_FOUND])
+		} else {
+			return nil, err
+		}
+	}
+
+	if bank.Account == member.Account {
+		return nil, errors.New(enums.UserErrors[enums.OWN_BANK])
+	}
+
+	// every invite works once, the role of an existing member is replaced by the new one
+	var invites []models.Invite
+	for _, invite := range bank.Invites {
+		if invite.Code == code {
+			member.Role = invite.Role
+		} else {
+			invites = append(invites, invite)
+		}
+	}
+
+	members := []models.Member{member}
+	for _, existing := range bank.Members {
+		if existing.Account != member.Account {
+			members = append(members, existing)
+		}
+	}
+
+	if err = bank.Update(ctx, db, bson.M{
+		"invites": invites,
+		"members": members,
+	}); err != nil {
+		return nil, err
+	}
+
+	return bank, nil
+}
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func ApproveOperation(ctx context.Context, db *models.DataBase, operation *models.Operation, approver *models.User) (*models.Bank, error) {
+	bank, err := GetBankById(ctx, db, operation.Account, operation.Bank)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := operation.Amount
+
+	var backup *models.Bank
+	var shortfall int
+
+	if operation.Operation == enums.BotCommands[enums.EXPENSE] {
+		amount = -amount
+
+		if backup, shortfall, err = GetOverdraftCover(ctx, db, bank, operation.Amount); err != nil {
+			return nil, err
+		}
+
+		if shortfall > 0 && backup == nil && bank.Overdraft == enums.OverdraftPolicies[enums.FORBID] {
+			return nil, errors.New(enums.UserErrors[enums.CANNOT_APPROVE])
+		}
+	}
+
+	// the request stops being pending at once, so two admins can't apply it twice
+	result, err := db.Collections["operations"].UpdateOne(
+		ctx,
+		bson.M{
+			"id":      operation.Id,
+			"pending": true,
+		},
+		bson.M{"$unset": bson.M{"pending": ""}},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.ModifiedCount < 1 {
+		return nil, errors.New(enums.UserErrors[enums.REQUEST_NOT_FOUND])
+	}
+
+	operation.Pending = false
+
+	if backup != nil {
+		cover, err := CreateTransfer(ctx, db, backup, bank, shortfall, approver)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = operation.Update(ctx, db, bson.M{"cover": cover.Transfer}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = bank.Increase(ctx, db, amount); err != nil {
+		return nil, err
+	}
+
+	return bank, nil
+}
@@ -0,0 +1,16 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"errors"
+)
+
+func CheckRole(bank *models.Bank, account int, role enums.Role) error {
+	// roles are declared from the weakest to the strongest one
+	if GetRole(bank, account) < role {
+		return errors.New(enums.UserErrors[enums.NO_PERMISSION])
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func CloseBank(ctx context.Context, db *models.DataBase, bank *models.Bank, target *models.Bank, author *models.User) error {
+	if target != nil && target.Id == bank.Id {
+		return errors.New(enums.UserErrors[enums.SAME_BANK])
+	}
+
+	if target != nil && bank.Balance > 0 {
+		if _, err := CreateTransfer(ctx, db, bank, target, bank.Balance, author); err != nil {
+			return err
+		}
+	} else if target != nil && bank.Balance < 0 {
+		if _, err := CreateTransfer(ctx, db, target, bank, -bank.Balance, author); err != nil {
+			return err
+		}
+	}
+
+	// nested banks are moved one level up
+	if _, err := db.Collections["banks"].UpdateMany(
+		ctx,
+		bson.M{
+			"account": bank.Account,
+			"parent":  bank.Id,
+		},
+		bson.M{
+			"$set": bson.M{"parent": bank.Parent},
+		},
+	); err != nil {
+		return err
+	}
+
+	return bank.Destroy(ctx, db)
+}
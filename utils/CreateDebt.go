@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+)
+
+func CreateDebt(ctx context.Context, db *models.DataBase, debt *models.Debt, bank *models.Bank, author *models.User) error {
+	if err := debt.Create(ctx, db); err != nil {
+		return err
+	}
+
+	if bank == nil {
+		return nil
+	}
+
+	// lent money leaves the bank, borrowed money comes into it
+	operation := models.Operation{
+		Account:   bank.Account,
+		Operation: enums.BotCommands[enums.EXPENSE],
+		Amount:    debt.Amount,
+		Comment:   "Долг: " + debt.Person,
+		Author:    author,
+		Debt:      debt.Id,
+	}
+
+	if debt.Direction == enums.DebtDirections[enums.BORROWED] {
+		operation.Operation = enums.BotCommands[enums.INCOME]
+	}
+
+	return CreateOperation(ctx, db, bank, &operation)
+}
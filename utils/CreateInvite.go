@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func CreateInvite(ctx context.Context, db *models.DataBase, bank *models.Bank, role enums.Role) (string, error) {
+	// the code is passed in a /start link, which allows only latin letters, digits, "_" and "-"
+	code, err := gonanoid.New()
+	if err != nil {
+		return "", err
+	}
+
+	invites := append(bank.Invites, models.Invite{
+		Code: code,
+		Role: enums.Roles[role],
+	})
+
+	if err = bank.Update(ctx, db, bson.M{"invites": invites}); err != nil {
+		return "", err
+	}
+
+	return code, nil
+}
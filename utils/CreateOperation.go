@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+)
+
+func CreateOperation(ctx context.Context, db *models.DataBase, bank *models.Bank, operation *models.Operation) error {
+	operation.Bank = bank.Id
+
+	if err := operation.Create(ctx, db); err != nil {
+		return err
+	}
+
+	change := operation.Amount
+	if operation.Operation == enums.BotCommands[enums.EXPENSE] {
+		change = -operation.Amount
+	}
+
+	if err := bank.Increase(ctx, db, change); err != nil {
+		return err
+	}
+
+	return nil
+}
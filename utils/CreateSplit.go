@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"BIEAS_bot/models"
+	"context"
+)
+
+func CreateSplit(ctx context.Context, db *models.DataBase, split *models.Split, participants []models.Participant) error {
+	split.Shares = []models.Share{}
+
+	// the amount is split evenly, and the rubles that are left over go to the first participants
+	for index, participant := range participants {
+		share := split.Amount / len(participants)
+		if index < split.Amount%len(participants) {
+			share++
+		}
+
+		split.Shares = append(split.Shares, models.Share{
+			Participant: participant,
+			Amount:      share,
+		})
+	}
+
+	return split.Create(ctx, db)
+}
@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+func CreateTransfer(ctx context.Context, db *models.DataBase, from *models.Bank, to *models.Bank, amount int, author *models.User) (*models.Operation, error) {
+	transfer, err := gonanoid.New()
+	if err != nil {
+		return nil, err
+	}
+
+	// every leg belongs to the account of its bank, as banks may be shared from different accounts
+	expense := models.Operation{
+		Account:   from.Account,
+		Bank:      from.Id,
+		Operation: enums.BotCommands[enums.EXPENSE],
+		Amount:    amount,
+		Comment:   "Перевод в копилку " + to.Name,
+		Transfer:  transfer,
+		Author:    author,
+	}
+
+	if err = expense.Create(ctx, db); err != nil {
+		return nil, err
+	}
+
+	if err = from.Increase(ctx, db, -amount); err != nil {
+		return nil, err
+	}
+
+	income := models.Operation{
+		Account:   to.Account,
+		Bank:      to.Id,
+		Operation: enums.BotCommands[enums.INCOME],
+		Amount:    amount,
+		Comment:   "Перевод из копилки " + from.Name,
+		Transfer:  transfer,
+		Author:    author,
+	}
+
+	if err = income.Create(ctx, db); err != nil {
+		return nil, err
+	}
+
+	if err = to.Increase(ctx, db, amount); err != nil {
+		return nil, err
+	}
+
+	return &expense, nil
+}
@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func DecodeFilter(data string) (models.Filter, int, error) {
+	var filter models.Filter
+
+	fields := strings.Split(data, ":")
+	if len(fields) != 6 {
+		return filter, 0, errors.New(enums.UserErrors[enums.INCORRECT_VALUE])
+	}
+
+	page, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return filter, 0, err
+	}
+
+	if fields[2] != "-" {
+		filter.Bank = fields[2]
+	}
+
+	for _, command := range []enums.BotCommand{enums.INCOME, enums.EXPENSE, enums.CREATE_TRANSFER} {
+		if enums.BotCommands[command][1:2] == fields[3] {
+			filter.Type = enums.BotCommands[command]
+		}
+	}
+
+	if fields[4] != "-" {
+		if filter.From, err = time.ParseInLocation("20060102", fields[4], time.Local); err != nil {
+			return filter, 0, err
+		}
+	}
+
+	if fields[5] != "-" {
+		if filter.To, err = time.ParseInLocation("20060102", fields[5], time.Local); err != nil {
+			return filter, 0, err
+		}
+	}
+
+	return filter, page, nil
+}
@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"bytes"
+	"errors"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+)
+
+func DecodeQR(data []byte) (string, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", errors.New(enums.UserErrors[enums.INCORRECT_RECEIPT])
+	}
+
+	bitmap, err := gozxing.NewBinaryBitmapFromImage(img)
+	if err != nil {
+		return "", errors.New(enums.UserErrors[enums.INCORRECT_RECEIPT])
+	}
+
+	result, err := qrcode.NewQRCodeReader().Decode(bitmap, map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_TRY_HARDER: true,
+	})
+	if err != nil {
+		return "", errors.New(enums.UserErrors[enums.INCORRECT_RECEIPT])
+	}
+
+	return result.GetText(), nil
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"errors"
+)
+
+func DestroyOperation(ctx context.Context, db *models.DataBase, operation *models.Operation) ([]*models.Bank, error) {
+	if operation.Reverted || operation.Reverts != "" {
+		return nil, errors.New(enums.UserErrors[enums.OPERATION_IS_REVERTED])
+	}
+
+	if operation.Pending {
+		return nil, errors.New(enums.UserErrors[enums.OPERATION_IS_PENDING])
+	}
+
+	legs, err := GetTransferLegs(ctx, db, operation.Transfer, operation.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	legs = append([]models.Operation{*operation}, legs...)
+
+	var banks []*models.Bank
+
+	for index := range legs {
+		bank, err := GetBankById(ctx, db, legs[index].Account, legs[index].Bank)
+		if err != nil {
+			return nil, err
+		}
+
+		change := -legs[index].Amount
+		if legs[index].Operation == enums.BotCommands[enums.EXPENSE] {
+			change = legs[index].Amount
+		}
+
+		if err = legs[index].Destroy(ctx, db); err != nil {
+			return nil, err
+		}
+
+		if err = bank.Increase(ctx, db, change); err != nil {
+			return nil, err
+		}
+
+		banks = append(banks, bank)
+	}
+
+	return banks, nil
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"BIEAS_bot/models"
+	"strings"
+)
+
+var columnNames = map[string][]string{
+	"date":        {"дата", "date"},
+	"amount":      {"сумма", "amount", "sum"},
+	"description": {"описание", "назначение", "комментарий", "description", "memo", "details", "payee"},
+}
+
+func DetectColumns(header []string) (models.Columns, bool) {
+	columns := models.Columns{Date: -1, Amount: -1, Description: -1}
+
+	for index, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		for column, keywords := range columnNames {
+			for _, keyword := range keywords {
+				if !strings.Contains(name, keyword) {
+					continue
+				}
+
+				// the first matching column wins, e.g. "Дата операции" before "Дата платежа"
+				switch {
+				case column == "date" && columns.Date < 0:
+					columns.Date = index
+				case column == "amount" && columns.Amount < 0:
+					columns.Amount = index
+				case column == "description" && columns.Description < 0:
+					columns.Description = index
+				}
+			}
+		}
+	}
+
+	return columns, columns.Date >= 0 && columns.Amount >= 0 && columns.Description >= 0
+}
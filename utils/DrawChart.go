@@ -0,0 +1,314 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"strconv"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	chartWidth  = 900
+	chartHeight = 540
+	chartLegend = 220
+)
+
+var chartColors = []color.RGBA{
+	{R: 0x4e, G: 0x79, B: 0xa7, A: 0xff},
+	{R: 0xe1, G: 0x57, B: 0x59, A: 0xff},
+	{R: 0x59, G: 0xa1, B: 0x4f, A: 0xff},
+	{R: 0xf2, G: 0x8e, B: 0x2b, A: 0xff},
+	{R: 0xb0, G: 0x7a, B: 0xa1, A: 0xff},
+	{R: 0x76, G: 0xb7, B: 0xb2, A: 0xff},
+	{R: 0xed, G: 0xc9, B: 0x48, A: 0xff},
+	{R: 0x9c, G: 0x75, B: 0x5f, A: 0xff},
+}
+
+var (
+	chartText = color.RGBA{R: 0x33, G: 0x33, B: 0x33, A: 0xff}
+	chartGrid = color.RGBA{R: 0xdd, G: 0xdd, B: 0xdd, A: 0xff}
+)
+
+func DrawChart(chart *models.Chart, kind enums.Chart) ([]byte, error) {
+	// the drawings divide by the number of labels and series and by the total of the pie
+	if len(chart.Labels) < 1 || len(chart.Series) < 1 {
+		return nil, errors.New(enums.UserErrors[enums.NO_CHART_DATA])
+	}
+
+	if kind == enums.EXPENSES_CHART {
+		total := 0
+		for _, value := range chart.Series[0].Values {
+			total += value
+		}
+
+		if total <= 0 {
+			return nil, errors.New(enums.UserErrors[enums.NO_CHART_DATA])
+		}
+	}
+
+	regular, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		return nil, err
+	}
+
+	title, err := opentype.NewFace(regular, &opentype.FaceOptions{Size: 20, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return nil, err
+	}
+	defer title.Close()
+
+	face, err := opentype.NewFace(regular, &opentype.FaceOptions{Size: 13, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return nil, err
+	}
+	defer face.Close()
+
+	img := image.NewRGBA(image.Rect(0, 0, chartWidth, chartHeight))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+
+	drawText(img, title, (chartWidth-font.MeasureString(title, chart.Title).Ceil())/2, 32, chart.Title, chartText)
+
+	plot := image.Rect(80, 60, chartWidth-chartLegend, chartHeight-50)
+
+	switch kind {
+	case enums.EXPENSES_CHART:
+		drawPie(img, face, plot, chart)
+	case enums.MONTHLY_CHART:
+		drawBars(img, face, plot, chart)
+	default:
+		drawLines(img, face, plot, chart)
+	}
+
+	var data bytes.Buffer
+	if err = png.Encode(&data, img); err != nil {
+		return nil, err
+	}
+
+	return data.Bytes(), nil
+}
+
+func drawLines(img *image.RGBA, face font.Face, plot image.Rectangle, chart *models.Chart) {
+	low, high := drawAxes(img, face, plot, chart, true)
+
+	x := func(index int) int {
+		if len(chart.Labels) < 2 {
+			return (plot.Min.X + plot.Max.X) / 2
+		}
+
+		return plot.Min.X + index*plot.Dx()/(len(chart.Labels)-1)
+	}
+
+	step := (len(chart.Labels) + 7) / 8
+	for index, label := range chart.Labels {
+		if index%step == 0 {
+			drawText(img, face, x(index)-font.MeasureString(face, label).Ceil()/2, plot.Max.Y+20, label, chartText)
+		}
+	}
+
+	for number, series := range chart.Series {
+		line := chartColors[number%len(chartColors)]
+
+		for index, value := range series.Values {
+			y := getChartY(plot, low, high, value)
+
+			if index == 0 {
+				fillRect(img, image.Rect(x(index)-2, y-2, x(index)+2, y+2), line)
+				continue
+			}
+
+			drawLine(img, x(index-1), getChartY(plot, low, high, series.Values[index-1]), x(index), y, line)
+		}
+
+		drawLegend(img, face, number, series.Name)
+	}
+}
+
+func drawBars(img *image.RGBA, face font.Face, plot image.Rectangle, chart *models.Chart) {
+	low, high := drawAxes(img, face, plot, chart, false)
+
+	group := plot.Dx() / len(chart.Labels)
+	width := group * 3 / 4 / len(chart.Series)
+
+	for index, label := range chart.Labels {
+		left := plot.Min.X + index*group + group/8
+
+		drawText(img, face, left+group*3/8-font.MeasureString(face, label).Ceil()/2, plot.Max.Y+20, label, chartText)
+
+		for number, series := range chart.Series {
+			bar := image.Rect(left+number*width, getChartY(plot, low, high, series.Values[index]), left+(number+1)*width-1, getChartY(plot, low, high, 0))
+			fillRect(img, bar, chartColors[number%len(chartColors)])
+		}
+	}
+
+	for number, series := range chart.Series {
+		drawLegend(img, face, number, series.Name)
+	}
+}
+
+func drawPie(img *image.RGBA, face font.Face, plot image.Rectangle, chart *models.Chart) {
+	values := chart.Series[0].Values
+
+	total := 0
+	for _, value := range values {
+		total += value
+	}
+
+	radius := plot.Dy() / 2
+	if plot.Dx()/2 < radius {
+		radius = plot.Dx() / 2
+	}
+
+	center := image.Pt((plot.Min.X+plot.Max.X)/2, (plot.Min.Y+plot.Max.Y)/2)
+
+	// every pixel of the circle is painted with the slice its angle from twelve o'clock falls into
+	for y := -radius; y <= radius; y++ {
+		for x := -radius; x <= radius; x++ {
+			if x*x+y*y > radius*radius {
+				continue
+			}
+
+			angle := math.Atan2(float64(x), float64(-y))
+			if angle < 0 {
+				angle += 2 * math.Pi
+			}
+
+			share := angle / (2 * math.Pi) * float64(total)
+
+			slice, sum := 0, 0
+			for slice < len(values)-1 && float64(sum+values[slice]) <= share {
+				sum += values[slice]
+				slice++
+			}
+
+			img.SetRGBA(center.X+x, center.Y+y, chartColors[slice%len(chartColors)])
+		}
+	}
+
+	for index, label := range chart.Labels {
+		drawLegend(img, face, index, label+" — "+strconv.Itoa(values[index])+" ("+strconv.Itoa(values[index]*100/total)+"%)")
+	}
+}
+
+// drawAxes draws the value grid and returns the range of values it covers
+func drawAxes(img *image.RGBA, face font.Face, plot image.Rectangle, chart *models.Chart, negative bool) (int, int) {
+	low, high := 0, 0
+	for _, series := range chart.Series {
+		for _, value := range series.Values {
+			if value < low && negative {
+				low = value
+			}
+			if value > high {
+				high = value
+			}
+		}
+	}
+
+	step := getChartStep((high - low) / 5)
+	low = int(math.Floor(float64(low)/float64(step))) * step
+	high = int(math.Ceil(float64(high)/float64(step))) * step
+	if high == low {
+		high = low + step
+	}
+
+	for value := low; value <= high; value += step {
+		y := getChartY(plot, low, high, value)
+
+		fillRect(img, image.Rect(plot.Min.X, y, plot.Max.X, y+1), chartGrid)
+
+		label := strconv.Itoa(value)
+		drawText(img, face, plot.Min.X-8-font.MeasureString(face, label).Ceil(), y+5, label, chartText)
+	}
+
+	return low, high
+}
+
+// getChartStep rounds the grid step up to 1, 2 or 5 multiplied by a power of ten
+func getChartStep(step int) int {
+	power := 1
+	for power*10 <= step {
+		power *= 10
+	}
+
+	for _, multiplier := range []int{1, 2, 5, 10} {
+		if power*multiplier >= step {
+			return power * multiplier
+		}
+	}
+
+	return power * 10
+}
+
+func getChartY(plot image.Rectangle, low int, high int, value int) int {
+	return plot.Max.Y - int(float64(value-low)/float64(high-low)*float64(plot.Dy()))
+}
+
+func drawLegend(img *image.RGBA, face font.Face, index int, text string) {
+	x, y := chartWidth-chartLegend+30, 70+index*24
+
+	fillRect(img, image.Rect(x, y-10, x+12, y+2), chartColors[index%len(chartColors)])
+	drawText(img, face, x+20, y, text, chartText)
+}
+
+func drawText(img *image.RGBA, face font.Face, x int, y int, text string, color color.Color) {
+	drawer := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(color),
+		Face: face,
+		Dot:  fixed.P(x, y),
+	}
+
+	drawer.DrawString(text)
+}
+
+func fillRect(img *image.RGBA, rect image.Rectangle, color color.Color) {
+	draw.Draw(img, rect.Canon(), image.NewUniform(color), image.Point{}, draw.Src)
+}
+
+// drawLine draws a two pixels thick segment with the Bresenham algorithm
+func drawLine(img *image.RGBA, x0 int, y0 int, x1 int, y1 int, color color.RGBA) {
+	dx, dy := x1-x0, y1-y0
+	if dx < 0 {
+		dx = -dx
+	}
+	if dy < 0 {
+		dy = -dy
+	}
+
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+
+	err := dx - dy
+	for {
+		fillRect(img, image.Rect(x0-1, y0-1, x0+1, y0+1), color)
+
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+
+		e := 2 * err
+		if e > -dy {
+			err -= dy
+			x0 += sx
+		}
+		if e < dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"BIEAS_bot/models"
+	"strconv"
+	"strings"
+)
+
+func EncodeFilter(prefix string, filter models.Filter, page int) string {
+	fields := []string{prefix, strconv.Itoa(page), "-", "-", "-", "-"}
+
+	if filter.Bank != "" {
+		fields[2] = filter.Bank
+	}
+
+	if filter.Type != "" {
+		fields[3] = filter.Type[1:2]
+	}
+
+	if !filter.From.IsZero() {
+		fields[4] = filter.From.Format("20060102")
+	}
+
+	if !filter.To.IsZero() {
+		fields[5] = filter.To.Format("20060102")
+	}
+
+	return strings.Join(fields, ":")
+}
@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"BIEAS_bot/models"
+	"strings"
+)
+
+func FindBank(banks []models.Bank, name string) *models.Bank {
+	normalized := normalizeBankName(name)
+	if normalized == "" {
+		return nil
+	}
+
+	for index := range banks {
+		if banks[index].Name == name {
+			return &banks[index]
+		}
+	}
+
+	for index := range banks {
+		if normalizeBankName(banks[index].Name) == normalized {
+			return &banks[index]
+		}
+	}
+
+	// the name is compared by stems, then by prefix and then by edit distance, and only a single match is accepted
+	matchers := []func(string) bool{
+		func(bankName string) bool {
+			return getStems(bankName) == getStems(normalized)
+		},
+		func(bankName string) bool {
+			return len([]rune(normalized)) >= 3 && strings.HasPrefix(bankName, normalized)
+		},
+		func(bankName string) bool {
+			if len([]rune(normalized)) < 4 {
+				return false
+			}
+
+			distance := 1
+			if len([]rune(normalized)) > 5 {
+				distance = 2
+			}
+
+			return getDistance(bankName, normalized) <= distance
+		},
+	}
+
+	for _, matcher := range matchers {
+		var found *models.Bank
+
+		for index := range banks {
+			if !matcher(normalizeBankName(banks[index].Name)) {
+				continue
+			}
+
+			if found != nil {
+				return nil
+			}
+
+			found = &banks[index]
+		}
+
+		if found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+func normalizeBankName(name string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.Join(strings.Fields(name), " ")), "ё", "е")
+}
+
+func getStems(name string) string {
+	var stems []string
+
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool { return r == ' ' || r == ':' || r == ',' }) {
+		stems = append(stems, GetStem(word))
+	}
+
+	return strings.Join(stems, " ")
+}
+
+func getDistance(first string, second string) int {
+	a, b := []rune(first), []rune(second)
+
+	previous := make([]int, len(b)+1)
+	for index := range previous {
+		previous[index] = index
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = previous[j-1] + cost
+			if previous[j]+1 < current[j] {
+				current[j] = previous[j] + 1
+			}
+			if current[j-1]+1 < current[j] {
+				current[j] = current[j-1] + 1
+			}
+		}
+
+		previous = current
+	}
+
+	return previous[len(b)]
+}
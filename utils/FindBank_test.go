@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"BIEAS_bot/models"
+	"testing"
+)
+
+func TestFindBank(t *testing.T) {
+	banks := []models.Bank{
+		{Id: "food", Name: "Еда"},
+		{Id: "salary", Name: "Зарплата"},
+		{Id: "vacation", Name: "Отпуск 2026"},
+		{Id: "tree", Name: "Ёлка"},
+		{Id: "car", Name: "Машина"},
+		{Id: "toy", Name: "Машинка"},
+		{Id: "books", Name: "Книги"},
+	}
+
+	tests := []struct {
+		name string
+		bank string
+	}{
+		{name: "Еда", bank: "food"},
+		{name: "еда", bank: "food"},
+		{name: "  ЕДА ", bank: "food"},
+		{name: "елка", bank: "tree"},
+		{name: "книгу", bank: "books"},
+		{name: "зарпл", bank: "salary"},
+		{name: "Зарплато", bank: "salary"},
+		{name: "отпуск", bank: "vacation"},
+		{name: "машину", bank: "car"},
+		{name: "маш", bank: ""},
+		{name: "ед", bank: ""},
+		{name: "кино", bank: ""},
+		{name: "", bank: ""},
+	}
+
+	for _, test := range tests {
+		bank := FindBank(banks, test.name)
+
+		id := ""
+		if bank != nil {
+			id = bank.Id
+		}
+
+		if id != test.bank {
+			t.Errorf("FindBank(%q) = %q, want %q", test.name, id, test.bank)
+		}
+	}
+}
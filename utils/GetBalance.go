@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"BIEAS_bot/models"
+	"context"
+	"strconv"
+)
+
+func GetBalance(ctx context.Context, db *models.DataBase, bank *models.Bank) (string, error) {
+	balance := "Баланс копилки " + bank.Name + " составляет " + strconv.Itoa(bank.Balance) + " руб."
+
+	banks, err := GetBanks(ctx, db, bank.Account)
+	if err != nil {
+		return "", err
+	}
+
+	descendants := GetDescendants(banks, bank.Id)
+	if len(descendants) < 1 {
+		return balance, nil
+	}
+
+	total := bank.Balance
+	for _, descendant := range descendants {
+		total += descendant.Balance
+	}
+
+	balance = "Баланс копилки " + bank.Name + " вместе с вложенными составляет " + strconv.Itoa(total) + " руб.%0A" +
+		"В самой копилке: " + strconv.Itoa(bank.Balance) + " руб."
+
+	for _, child := range banks {
+		if child.Parent != bank.Id {
+			continue
+		}
+
+		subtotal := child.Balance
+		for _, descendant := range GetDescendants(banks, child.Id) {
+			subtotal += descendant.Balance
+		}
+
+		balance += "%0A" + child.Name + ": " + strconv.Itoa(subtotal) + " руб."
+	}
+
+	return balance, nil
+}
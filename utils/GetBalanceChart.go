@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func GetBalanceChart(ctx context.Context, db *models.DataBase, account int, from time.Time, to time.Time) (*models.Chart, error) {
+	banks, err := GetBanks(ctx, db, account)
+	if err != nil {
+		return nil, err
+	}
+
+	var operations []models.Operation
+
+	documents, err := db.GetDocuments(
+		ctx,
+		"operations",
+		GetOperationsFilter(account, models.Filter{From: from}),
+		options.Find().SetSort(bson.M{"created_at": -1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer documents.Close(ctx)
+
+	if err = documents.All(ctx, &operations); err != nil {
+		return nil, err
+	}
+
+	if from.IsZero() {
+		if len(operations) < 1 {
+			return nil, errors.New(enums.UserErrors[enums.NO_CHART_DATA])
+		}
+
+		first := ParseCreatedAt(operations[len(operations)-1].CreatedAt).In(time.Local)
+		from = time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, time.Local)
+	}
+
+	// nested banks are drawn together with their top-level bank
+	var roots []models.Bank
+	for _, bank := range banks {
+		if bank.Parent == "" {
+			roots = append(roots, bank)
+		}
+	}
+
+	chart := &models.Chart{Title: enums.ChartNames[enums.BALANCE_CHART] + ", руб."}
+
+	balances := make([]int, len(roots))
+	indexes := make(map[string]int)
+
+	for index, root := range roots {
+		indexes[root.Id] = index
+		balances[index] = root.Balance
+
+		for _, descendant := range GetDescendants(banks, root.Id) {
+			indexes[descendant.Id] = index
+			balances[index] += descendant.Balance
+		}
+
+		chart.Series = append(chart.Series, models.Series{Name: root.Name})
+	}
+
+	var days []time.Time
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
+	}
+
+	if len(days) < 1 {
+		return nil, errors.New(enums.UserErrors[enums.NO_CHART_DATA])
+	}
+
+	values := make([][]int, len(roots))
+	for index := range values {
+		values[index] = make([]int, len(days))
+	}
+
+	// the balances at the end of every day are restored by undoing the operations from the newest one
+	next := 0
+	for day := len(days) - 1; day >= 0; day-- {
+		end := days[day].AddDate(0, 0, 1).In(time.Local).Format("2006-01-02 15:04:05")
+
+		for ; next < len(operations) && operations[next].CreatedAt >= end; next++ {
+			index, ok := indexes[operations[next].Bank]
+			if !ok {
+				continue
+			}
+
+			if operations[next].Operation == enums.BotCommands[enums.INCOME] {
+				balances[index] -= operations[next].Amount
+			} else {
+				balances[index] += operations[next].Amount
+			}
+		}
+
+		for index := range roots {
+			values[index][day] = balances[index]
+		}
+	}
+
+	for _, day := range days {
+		chart.Labels = append(chart.Labels, day.Format("02.01"))
+	}
+
+	for index := range chart.Series {
+		chart.Series[index].Values = values[index]
+	}
+
+	return chart, nil
+}
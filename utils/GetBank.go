@@ -16,6 +16,13 @@ func GetBank(ctx context.Context, db *models.DataBase, account int, name string)
 		"account": account,
 		"name":    name,
 	}).Decode(&bank)
+	if err != nil && err.Error() == "mongo: no documents in result" {
+		// banks of other accounts shared with this one are found after the own ones
+		err = db.GetDocument(ctx, "banks", bson.M{
+			"members.account": account,
+			"name":            name,
+		}).Decode(&bank)
+	}
 	if err != nil {
 		if err.Error() == "mongo: no documents in result" {
 			return nil, errors.New(enums.UserErrors[enums.BANK_NOT_FOUND])
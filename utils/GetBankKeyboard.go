@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"errors"
+)
+
+func GetBankKeyboard(ctx context.Context, db *models.DataBase, account int, parent *models.Bank) ([]string, error) {
+	var keyboard []string
+
+	banks, err := GetBanks(ctx, db, account)
+	if err != nil && err.Error() != enums.UserErrors[enums.NO_BANKS] {
+		return nil, err
+	}
+
+	shared, err := GetSharedBanks(ctx, db, account)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(banks)+len(shared) < 1 {
+		return nil, errors.New(enums.UserErrors[enums.NO_BANKS])
+	}
+
+	level := ""
+	if parent != nil {
+		level = parent.Id
+		keyboard = append(keyboard, parent.Name)
+	}
+
+	for _, bank := range banks {
+		if bank.Parent != level {
+			continue
+		}
+
+		// banks with children open the next level of the keyboard instead of being chosen
+		if len(GetDescendants(banks, bank.Id)) > 0 {
+			keyboard = append(keyboard, bank.Name+" ▸")
+		} else {
+			keyboard = append(keyboard, bank.Name)
+		}
+	}
+
+	// shared banks are always chosen as a whole at the top level
+	if parent == nil {
+		for _, bank := range shared {
+			keyboard = append(keyboard, bank.Name)
+		}
+	}
+
+	if parent != nil {
+		keyboard = append(keyboard, "◂ Назад")
+	}
+
+	return keyboard, nil
+}
@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func GetBanks(ctx context.Context, db *models.DataBase, account int) ([]models.Bank, error) {
+	var banks []models.Bank
+
+	documents, err := db.GetDocuments(ctx, "banks", bson.M{"account": account})
+	if err != nil {
+		return nil, errors.New(enums.UserErrors[enums.UNEXPECTED_ERROR])
+	}
+	defer documents.Close(ctx)
+
+	if err = documents.All(ctx, &banks); err != nil {
+		return nil, errors.New(enums.UserErrors[enums.UNEXPECTED_ERROR])
+	}
+
+	if len(banks) < 1 {
+		return nil, errors.New(enums.UserErrors[enums.NO_BANKS])
+	}
+
+	return banks, nil
+}
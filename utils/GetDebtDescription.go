@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func GetDebtDescription(ctx context.Context, db *models.DataBase, debt *models.Debt) string {
+	person := url.QueryEscape(debt.Person)
+
+	description := person + " — должен тебе " + strconv.Itoa(debt.Amount-debt.Repaid) + " руб."
+	if debt.Direction == enums.DebtDirections[enums.BORROWED] {
+		description = person + " — ты должен " + strconv.Itoa(debt.Amount-debt.Repaid) + " руб."
+	}
+
+	if debt.Repaid > 0 {
+		description += "%0AВозвращено " + strconv.Itoa(debt.Repaid) + " из " + strconv.Itoa(debt.Amount) + " руб."
+	}
+
+	if debt.Bank != "" {
+		var bank *models.Bank
+
+		// the bank may be shared by another account, and its id is unique anyway
+		if err := db.GetDocument(ctx, "banks", bson.M{"id": debt.Bank}).Decode(&bank); err == nil {
+			description += "%0AКопилка: " + bank.Name
+		}
+	}
+
+	if !debt.Due.IsZero() {
+		description += "%0AВернуть до " + debt.Due.Format("02.01.2006")
+
+		if debt.Due.Before(GetPeriodStart(enums.Periods[enums.DAY], time.Now())) {
+			description += " — срок прошёл"
+		}
+	}
+
+	return description
+}
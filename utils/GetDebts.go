@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"errors"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func GetDebts(ctx context.Context, db *models.DataBase, account int) ([]models.Debt, []string, error) {
+	var debts []models.Debt
+	var labels []string
+
+	documents, err := db.GetDocuments(ctx, "debts", bson.M{
+		"account": account,
+		"closed":  false,
+	})
+	if err != nil {
+		return nil, nil, errors.New(enums.UserErrors[enums.UNEXPECTED_ERROR])
+	}
+	defer documents.Close(ctx)
+
+	if err = documents.All(ctx, &debts); err != nil {
+		return nil, nil, errors.New(enums.UserErrors[enums.UNEXPECTED_ERROR])
+	}
+
+	if len(debts) < 1 {
+		return nil, nil, errors.New(enums.UserErrors[enums.NO_DEBTS])
+	}
+
+	for index, debt := range debts {
+		var name string
+
+		for direction, value := range enums.DebtDirections {
+			if value == debt.Direction {
+				name = enums.DebtDirectionNames[direction]
+			}
+		}
+
+		labels = append(
+			labels,
+			strconv.Itoa(index+1)+". "+name+": "+debt.Person+" "+strconv.Itoa(debt.Amount-debt.Repaid)+" руб.",
+		)
+	}
+
+	return debts, labels, nil
+}
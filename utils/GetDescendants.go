@@ -0,0 +1,25 @@
+package utils
+
+import "BIEAS_bot/models"
+
+func GetDescendants(banks []models.Bank, id string) []models.Bank {
+	var descendants []models.Bank
+
+	parents := []string{id}
+	for len(parents) > 0 {
+		var children []string
+
+		for _, bank := range banks {
+			for _, parent := range parents {
+				if bank.Parent == parent {
+					descendants = append(descendants, bank)
+					children = append(children, bank.Id)
+				}
+			}
+		}
+
+		parents = children
+	}
+
+	return descendants
+}
@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func GetDigest(ctx context.Context, db *models.DataBase, account int, from time.Time) (string, error) {
+	banks, err := GetBanks(ctx, db, account)
+	if err != nil {
+		return "", err
+	}
+
+	// bank names go straight into the message, so characters like & must not cut it off
+	bankNames := make(map[string]string)
+	for _, bank := range banks {
+		bankNames[bank.Id] = url.QueryEscape(bank.Name)
+	}
+
+	documents, err := db.GetDocuments(
+		ctx,
+		"operations",
+		bson.M{
+			"account":    account,
+			"pending":    bson.M{"$ne": true},
+			"created_at": bson.M{"$gte": from.In(time.Local).Format("2006-01-02 15:04:05")},
+		},
+		options.Find().SetSort(bson.M{"created_at": 1}),
+	)
+	if err != nil {
+		return "", err
+	}
+	defer documents.Close(ctx)
+
+	var operations []models.Operation
+
+	if err = documents.All(ctx, &operations); err != nil {
+		return "", err
+	}
+
+	var digest string
+	var income, expense int
+
+	if len(operations) < 1 {
+		digest += "Операций за этот период не было%0A"
+	} else {
+		digest += "Операции:%0A"
+	}
+
+	for index, operation := range operations {
+		sign := "+"
+		if operation.Operation == enums.BotCommands[enums.EXPENSE] {
+			sign = "-"
+		}
+
+		if operation.Transfer == "" {
+			if sign == "+" {
+				income += operation.Amount
+			} else {
+				expense += operation.Amount
+			}
+		}
+
+		if index == 15 {
+			digest += "…и ещё " + strconv.Itoa(len(operations)-index) + "%0A"
+		}
+
+		if index >= 15 {
+			continue
+		}
+
+		digest += url.QueryEscape(sign) + strconv.Itoa(operation.Amount) + " руб. " + bankNames[operation.Bank]
+		if operation.Comment != "" {
+			digest += " (" + url.QueryEscape(operation.Comment) + ")"
+		}
+
+		digest += "%0A"
+	}
+
+	digest += "%0AДоходы: " + strconv.Itoa(income) + " руб.%0AРасходы: " + strconv.Itoa(expense) + " руб.%0A"
+
+	digest += "%0AБалансы копилок:%0A"
+	for _, bank := range banks {
+		digest += url.QueryEscape(bank.Name) + ": " + strconv.Itoa(bank.Balance) + " руб.%0A"
+	}
+
+	var limits, goals string
+
+	for index := range banks {
+		if banks[index].Limit > 0 {
+			periodStart := GetPeriodStart(banks[index].Period, time.Now())
+
+			spent, err := GetSpent(ctx, db, &banks[index], periodStart)
+			if err != nil {
+				return "", err
+			}
+
+			limits += url.QueryEscape(banks[index].Name) + "%0A" + GetLimitProgress(&banks[index], spent) + "%0A"
+		}
+
+		if banks[index].Goal > 0 {
+			goals += url.QueryEscape(banks[index].Name) + "%0A" + GetGoalProgress(&banks[index]) + "%0A"
+		}
+	}
+
+	if limits != "" {
+		digest += "%0AЛимиты:%0A" + limits
+	}
+
+	if goals != "" {
+		digest += "%0AЦели:%0A" + goals
+	}
+
+	return digest, nil
+}
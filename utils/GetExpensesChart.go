@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+func GetExpensesChart(ctx context.Context, db *models.DataBase, account int, from time.Time, to time.Time) (*models.Chart, error) {
+	banks, err := GetBanks(ctx, db, account)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := GetTotals(ctx, db, account, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	if report.Expense <= 0 {
+		return nil, errors.New(enums.UserErrors[enums.NO_CHART_DATA])
+	}
+
+	var slices []models.Series
+	for _, bank := range banks {
+		if totals, ok := report.Banks[bank.Id]; ok && totals.Expense > 0 {
+			slices = append(slices, models.Series{Name: bank.Name, Values: []int{totals.Expense}})
+		}
+	}
+
+	sort.SliceStable(slices, func(i, j int) bool {
+		return slices[i].Values[0] > slices[j].Values[0]
+	})
+
+	// small slices are unreadable, so everything after the seventh bank is drawn as one slice
+	if len(slices) > 8 {
+		other := models.Series{Name: "Другие", Values: []int{0}}
+		for _, slice := range slices[7:] {
+			other.Values[0] += slice.Values[0]
+		}
+
+		slices = append(slices[:7], other)
+	}
+
+	chart := &models.Chart{
+		Title:  enums.ChartNames[enums.EXPENSES_CHART] + ", руб.",
+		Series: []models.Series{{Name: "Расходы"}},
+	}
+
+	for _, slice := range slices {
+		chart.Labels = append(chart.Labels, slice.Name)
+		chart.Series[0].Values = append(chart.Series[0].Values, slice.Values[0])
+	}
+
+	return chart, nil
+}
@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"strconv"
+	"time"
+)
+
+func GetExport(ctx context.Context, db *models.DataBase, account int, from time.Time, to time.Time) ([]models.Sheet, error) {
+	banks, err := GetBanks(ctx, db, account)
+	if err != nil {
+		return nil, err
+	}
+
+	operations, err := GetExportOperations(ctx, db, account, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	bankNames := make(map[string]string)
+	for _, bank := range banks {
+		bankNames[bank.Id] = bank.Name
+	}
+
+	// both legs of a transfer share the transfer id, so every leg can name the other bank
+	legs := make(map[string][]models.Operation)
+	for _, operation := range operations {
+		if operation.Transfer != "" {
+			legs[operation.Transfer] = append(legs[operation.Transfer], operation)
+		}
+	}
+
+	rows := [][]string{{"Дата", "Копилка", "Тип", "Сумма", "Комментарий", "Перевод", "Связанная копилка"}}
+
+	for _, operation := range operations {
+		kind := enums.OperationNames[enums.INCOME]
+		amount := operation.Amount
+
+		if operation.Operation == enums.BotCommands[enums.EXPENSE] {
+			kind = enums.OperationNames[enums.EXPENSE]
+			amount = -amount
+		}
+
+		var related string
+		if operation.Transfer != "" {
+			kind = enums.OperationNames[enums.CREATE_TRANSFER]
+
+			for _, leg := range legs[operation.Transfer] {
+				if leg.Id != operation.Id {
+					related = bankNames[leg.Bank]
+				}
+			}
+		}
+
+		rows = append(rows, []string{
+			ParseCreatedAt(operation.CreatedAt).In(time.Local).Format("2006-01-02 15:04"),
+			bankNames[operation.Bank],
+			kind,
+			strconv.Itoa(amount),
+			operation.Comment,
+			operation.Transfer,
+			related,
+		})
+	}
+
+	bankRows := [][]string{{"Копилка", "Родительская копилка", "Баланс", "Цель", "Лимит"}}
+
+	for _, bank := range banks {
+		bankRows = append(bankRows, []string{
+			bank.Name,
+			bankNames[bank.Parent],
+			strconv.Itoa(bank.Balance),
+			strconv.Itoa(bank.Goal),
+			strconv.Itoa(bank.Limit),
+		})
+	}
+
+	return []models.Sheet{
+		{Name: "Операции", Rows: rows},
+		{Name: "Копилки", Rows: bankRows},
+	}, nil
+}
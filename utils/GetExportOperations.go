@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"BIEAS_bot/models"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func GetExportOperations(ctx context.Context, db *models.DataBase, account int, from time.Time, to time.Time) ([]models.Operation, error) {
+	var operations []models.Operation
+
+	filter := GetOperationsFilter(account, models.Filter{From: from, To: to})
+	filter["reverted"] = bson.M{"$ne": true}
+	filter["reverts"] = bson.M{"$exists": false}
+
+	documents, err := db.GetDocuments(ctx, "operations", filter, options.Find().SetSort(bson.M{"created_at": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer documents.Close(ctx)
+
+	if err = documents.All(ctx, &operations); err != nil {
+		return nil, err
+	}
+
+	return operations, nil
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"BIEAS_bot/models"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func GetGoalProgress(bank *models.Bank) string {
+	if bank.Goal <= 0 {
+		return ""
+	}
+
+	percent := 0
+	if bank.Balance > 0 {
+		percent = bank.Balance * 100 / bank.Goal
+	}
+
+	filled := percent / 10
+	if filled > 10 {
+		filled = 10
+	}
+
+	progress := "Цель: " + strconv.Itoa(bank.Goal) + " руб."
+	if !bank.Deadline.IsZero() {
+		progress += " до " + bank.Deadline.Format("02.01.2006")
+	}
+
+	progress += "%0A" + strings.Repeat("▓", filled) + strings.Repeat("░", 10-filled) +
+		" " + strconv.Itoa(percent) + "%25"
+
+	if bank.Balance >= bank.Goal {
+		return progress + "%0AЦель достигнута!"
+	}
+
+	if !bank.Deadline.IsZero() {
+		left := time.Until(bank.Deadline)
+		if left <= 0 {
+			return progress + "%0AСрок достижения цели истёк"
+		}
+
+		months := int(math.Ceil(left.Hours() / (24 * 30.4375)))
+		contribution := int(math.Ceil(float64(bank.Goal-bank.Balance) / float64(months)))
+
+		progress += "%0AЧтобы успеть к сроку, откладывай по " + strconv.Itoa(contribution) + " руб. в месяц"
+	}
+
+	return progress
+}
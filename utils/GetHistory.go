@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const historyPageSize = 10
+
+func GetHistory(ctx context.Context, db *models.DataBase, account int, filter models.Filter, page int) (string, [][]models.InlineKeyboardButton, error) {
+	bankNames := make(map[string]string)
+
+	banks, err := GetBanks(ctx, db, account)
+	if err != nil && err.Error() != enums.UserErrors[enums.NO_BANKS] {
+		return "", nil, err
+	}
+
+	for _, bank := range banks {
+		bankNames[bank.Id] = url.QueryEscape(bank.Name)
+	}
+
+	documents, err := db.GetDocuments(
+		ctx,
+		"operations",
+		GetOperationsFilter(account, filter),
+		options.Find().
+			SetSort(bson.D{{Key: "created_at", Value: -1}}).
+			SetSkip(int64(page*historyPageSize)).
+			SetLimit(historyPageSize+1),
+	)
+	if err != nil {
+		return "", nil, err
+	}
+	defer documents.Close(ctx)
+
+	var operations []models.Operation
+
+	if err = documents.All(ctx, &operations); err != nil {
+		return "", nil, err
+	}
+
+	hasNext := len(operations) > historyPageSize
+	if hasNext {
+		operations = operations[:historyPageSize]
+	}
+
+	history := "История операций, страница " + strconv.Itoa(page+1) + "%0A%0A"
+
+	if len(operations) < 1 {
+		history += "Операций не найдено"
+	}
+
+	var income, expense int
+	var buttons [][]models.InlineKeyboardButton
+
+	for index, operation := range operations {
+		sign := "+"
+		if operation.Operation == enums.BotCommands[enums.EXPENSE] {
+			sign = "-"
+		}
+
+		if operation.Transfer == "" && sign == "+" {
+			income += operation.Amount
+		} else if operation.Transfer == "" {
+			expense += operation.Amount
+		}
+
+		// every operation can be opened for editing by its number
+		if index%5 == 0 {
+			buttons = append(buttons, []models.InlineKeyboardButton{})
+		}
+
+		buttons[len(buttons)-1] = append(buttons[len(buttons)-1], models.InlineKeyboardButton{
+			Text:         strconv.Itoa(index + 1),
+			CallbackData: "operation:" + operation.Id,
+		})
+
+		history += strconv.Itoa(index+1) + ". " + ParseCreatedAt(operation.CreatedAt).In(time.Local).Format("02.01.2006 15:04") + " · " +
+			bankNames[operation.Bank] + " · " + url.QueryEscape(sign) + strconv.Itoa(operation.Amount) + " руб."
+
+		if operation.Comment != "" {
+			history += " · " + url.QueryEscape(operation.Comment)
+		}
+
+		// group chats have negative ids and are shared by several members
+		if account < 0 && operation.Author != nil {
+			history += " · " + url.QueryEscape(operation.Author.Name())
+		}
+
+		history += "%0A"
+	}
+
+	if len(operations) > 0 {
+		history += "%0AИтого на странице: доходы " + strconv.Itoa(income) + " руб., расходы " +
+			strconv.Itoa(expense) + " руб."
+	}
+
+	if len(operations) > 0 {
+		history += "%0A%0AНажми на номер операции, чтобы изменить или удалить её"
+	}
+
+	var navigation []models.InlineKeyboardButton
+
+	if page > 0 {
+		navigation = append(navigation, models.InlineKeyboardButton{
+			Text:         "◀ Назад",
+			CallbackData: EncodeFilter("history", filter, page-1),
+		})
+	}
+
+	if hasNext {
+		navigation = append(navigation, models.InlineKeyboardButton{
+			Text:         "Вперёд ▶",
+			CallbackData: EncodeFilter("history", filter, page+1),
+		})
+	}
+
+	buttons = append(buttons, navigation)
+
+	return history, buttons, nil
+}
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func GetLastOperation(ctx context.Context, db *models.DataBase, user int, since time.Time) (*models.Operation, error) {
+	var operation *models.Operation
+
+	// operations are found by their author, as shared banks keep them under the account of the owner
+	// and group banks under the account of the group
+	err := db.Collections["operations"].FindOne(
+		ctx,
+		bson.M{
+			"author.id":  user,
+			"reverted":   bson.M{"$ne": true},
+			"reverts":    bson.M{"$exists": false},
+			"pending":    bson.M{"$ne": true},
+			"created_at": bson.M{"$gte": since.In(time.Local).Format("2006-01-02 15:04:05")},
+		},
+		options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}}),
+	).Decode(&operation)
+	if err != nil {
+		if err.Error() == "mongo: no documents in result" {
+			return nil, errors.New(enums.UserErrors[enums.NOTHING_TO_UNDO])
+		} else {
+			return nil, err
+		}
+	}
+
+	return operation, nil
+}
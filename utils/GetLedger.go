@@ -0,0 +1,138 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+func GetLedger(ctx context.Context, db *models.DataBase, account int, from time.Time, to time.Time, beancount bool) (string, error) {
+	settings, err := GetSettings(ctx, db, account)
+	if err != nil {
+		return "", err
+	}
+
+	income, expense := settings.Income, settings.Expense
+	if income == "" {
+		income = "Income:Прочее"
+	}
+	if expense == "" {
+		expense = "Expenses:Прочее"
+	}
+
+	list, err := GetBanks(ctx, db, account)
+	if err != nil {
+		return "", err
+	}
+
+	banks := make(map[string]models.Bank)
+	for _, bank := range list {
+		banks[bank.Id] = bank
+	}
+
+	operations, err := GetExportOperations(ctx, db, account, from, to)
+	if err != nil {
+		return "", err
+	}
+
+	legs := make(map[string][]models.Operation)
+	for _, operation := range operations {
+		if operation.Transfer != "" {
+			legs[operation.Transfer] = append(legs[operation.Transfer], operation)
+		}
+	}
+
+	opened := make(map[string]string)
+
+	var entries []string
+
+	for _, operation := range operations {
+		date := ParseCreatedAt(operation.CreatedAt).In(time.Local).Format("2006-01-02")
+		amount := strconv.Itoa(operation.Amount) + " RUB"
+
+		target := GetLedgerAccount(banks, operation.Bank)
+		source := income
+		if operation.Operation == enums.BotCommands[enums.EXPENSE] {
+			target, source = expense, target
+		}
+
+		// a transfer is written once as a single transaction between two asset accounts
+		if operation.Transfer != "" {
+			if operation.Operation != enums.BotCommands[enums.EXPENSE] {
+				if len(legs[operation.Transfer]) > 1 {
+					continue
+				}
+
+				source = "Equity:Переводы"
+			} else {
+				target = "Equity:Переводы"
+
+				for _, leg := range legs[operation.Transfer] {
+					if leg.Id != operation.Id {
+						target = GetLedgerAccount(banks, leg.Bank)
+					}
+				}
+			}
+		}
+
+		if beancount {
+			target, source = getBeancountAccount(target), getBeancountAccount(source)
+		}
+
+		for _, name := range []string{target, source} {
+			if _, ok := opened[name]; !ok {
+				opened[name] = date
+			}
+		}
+
+		description := strings.Join(strings.Fields(operation.Comment), " ")
+
+		if beancount {
+			entries = append(entries, date+" * \""+strings.ReplaceAll(description, "\"", "'")+"\"\n"+
+				"  "+target+"  "+amount+"\n"+
+				"  "+source+"  -"+amount)
+		} else {
+			entries = append(entries, date+" "+description+"\n"+
+				"    "+target+"  "+amount+"\n"+
+				"    "+source+"  -"+amount)
+		}
+	}
+
+	var header []string
+
+	// beancount requires every account to be opened before it is used
+	if beancount {
+		for name, date := range opened {
+			header = append(header, date+" open "+name)
+		}
+
+		sort.Strings(header)
+	}
+
+	return strings.Join(append(header, entries...), "\n\n") + "\n", nil
+}
+
+func getBeancountAccount(name string) string {
+	var components []string
+
+	for _, component := range strings.Split(name, ":") {
+		component = strings.Join(strings.FieldsFunc(component, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}), "-")
+
+		runes := []rune(component)
+		if len(runes) < 1 {
+			runes = []rune("X")
+		}
+
+		runes[0] = unicode.ToUpper(runes[0])
+		components = append(components, string(runes))
+	}
+
+	return strings.Join(components, ":")
+}
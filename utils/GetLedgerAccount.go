@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"BIEAS_bot/models"
+	"strings"
+)
+
+func GetLedgerAccount(banks map[string]models.Bank, id string) string {
+	var path []string
+
+	// the parents are walked up to the top level, a broken tree cannot loop because of the limit
+	for depth := 0; id != "" && depth < len(banks); depth++ {
+		bank, ok := banks[id]
+		if !ok {
+			break
+		}
+
+		path = append([]string{bank.Name}, path...)
+		id = bank.Parent
+	}
+
+	if len(path) < 1 {
+		path = []string{"Удалённая копилка"}
+	}
+
+	return "Assets:" + strings.Join(path, ":")
+}
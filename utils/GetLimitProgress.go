@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"strconv"
+	"strings"
+)
+
+func GetLimitProgress(bank *models.Bank, spent int) string {
+	if bank.Limit <= 0 {
+		return ""
+	}
+
+	period := "месяц"
+	if bank.Period == enums.Periods[enums.WEEK] {
+		period = "неделю"
+	} else if bank.Period == enums.Periods[enums.DAY] {
+		period = "день"
+	}
+
+	percent := spent * 100 / bank.Limit
+
+	filled := percent / 10
+	if filled > 10 {
+		filled = 10
+	}
+
+	progress := "Лимит на " + period + ": потрачено " + strconv.Itoa(spent) + " из " + strconv.Itoa(bank.Limit) +
+		" руб.%0A" + strings.Repeat("▓", filled) + strings.Repeat("░", 10-filled) + " " + strconv.Itoa(percent) + "%25"
+
+	if spent > bank.Limit {
+		progress += "%0AЛимит превышен на " + strconv.Itoa(spent-bank.Limit) + " руб."
+	}
+
+	return progress
+}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func GetLocation(timezone string) (*time.Location, error) {
+	if timezone == "" {
+		return time.Local, nil
+	}
+
+	offset := strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(timezone)), "UTC")
+	if hours, err := strconv.Atoi(offset); err == nil {
+		if hours < -12 || hours > 14 {
+			return nil, errors.New(enums.UserErrors[enums.INCORRECT_TIMEZONE])
+		}
+
+		name := "UTC" + offset
+		if hours >= 0 && !strings.HasPrefix(offset, "+") {
+			name = "UTC+" + offset
+		}
+
+		return time.FixedZone(name, hours*60*60), nil
+	}
+
+	location, err := time.LoadLocation(strings.TrimSpace(timezone))
+	if err != nil {
+		return nil, errors.New(enums.UserErrors[enums.INCORRECT_TIMEZONE])
+	}
+
+	return location, nil
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"errors"
+	"time"
+)
+
+func GetMonthlyChart(ctx context.Context, db *models.DataBase, account int, from time.Time, to time.Time) (*models.Chart, error) {
+	last := to.Add(-time.Nanosecond)
+	last = time.Date(last.Year(), last.Month(), 1, 0, 0, 0, 0, last.Location())
+
+	// no more than a year of months fits on the chart
+	month := last.AddDate(0, -11, 0)
+	if !from.IsZero() && from.After(month) {
+		month = time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, from.Location())
+	}
+
+	chart := &models.Chart{
+		Title:  enums.ChartNames[enums.MONTHLY_CHART] + ", руб.",
+		Series: []models.Series{{Name: "Доходы"}, {Name: "Расходы"}},
+	}
+
+	empty := true
+
+	for ; !month.After(last); month = month.AddDate(0, 1, 0) {
+		start, end := month, month.AddDate(0, 1, 0)
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+
+		report, err := GetTotals(ctx, db, account, start, end)
+		if err != nil {
+			return nil, err
+		}
+
+		if report.Income > 0 || report.Expense > 0 {
+			empty = false
+		}
+
+		chart.Labels = append(chart.Labels, month.Format("01.2006"))
+		chart.Series[0].Values = append(chart.Series[0].Values, report.Income)
+		chart.Series[1].Values = append(chart.Series[1].Values, report.Expense)
+	}
+
+	if empty {
+		return nil, errors.New(enums.UserErrors[enums.NO_CHART_DATA])
+	}
+
+	return chart, nil
+}
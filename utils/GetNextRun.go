@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func GetNextRun(cron string, after time.Time) (time.Time, error) {
+	fields := strings.Fields(cron)
+	if len(fields) != 5 {
+		return time.Time{}, errors.New(enums.UserErrors[enums.INCORRECT_SCHEDULE])
+	}
+
+	// minute, hour, day of month, month, day of week
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+
+	var sets [5]map[int]bool
+	for index, field := range fields {
+		set, err := parseCronField(field, bounds[index][0], bounds[index][1])
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		sets[index] = set
+	}
+
+	if sets[4][7] {
+		sets[4][0] = true
+	}
+
+	anyDay := fields[2] == "*" || fields[4] == "*"
+
+	day := time.Date(after.Year(), after.Month(), after.Day(), 0, 0, 0, 0, after.Location())
+	for offset := 0; offset < 366*5; offset++ {
+		date := day.AddDate(0, 0, offset)
+
+		if !sets[3][int(date.Month())] {
+			continue
+		}
+
+		dayOfMonth := sets[2][date.Day()]
+		dayOfWeek := sets[4][int(date.Weekday())]
+
+		if anyDay && !(dayOfMonth && dayOfWeek) || !anyDay && !(dayOfMonth || dayOfWeek) {
+			continue
+		}
+
+		for hour := 0; hour < 24; hour++ {
+			if !sets[1][hour] {
+				continue
+			}
+
+			for minute := 0; minute < 60; minute++ {
+				if !sets[0][minute] {
+					continue
+				}
+
+				run := time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, after.Location())
+				if run.After(after) {
+					return run, nil
+				}
+			}
+		}
+	}
+
+	return time.Time{}, errors.New(enums.UserErrors[enums.INCORRECT_SCHEDULE])
+}
+
+func parseCronField(field string, min int, max int) (map[int]bool, error) {
+	set := make(map[int]bool)
+	incorrect := errors.New(enums.UserErrors[enums.INCORRECT_SCHEDULE])
+
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if slash := strings.Index(part, "/"); slash >= 0 {
+			value, err := strconv.Atoi(part[slash+1:])
+			if err != nil || value < 1 {
+				return nil, incorrect
+			}
+
+			step = value
+			part = part[:slash]
+		}
+
+		from, to := min, max
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+
+			value, err := strconv.Atoi(bounds[0])
+			if err != nil {
+				return nil, incorrect
+			}
+
+			from = value
+			if len(bounds) == 2 {
+				if to, err = strconv.Atoi(bounds[1]); err != nil {
+					return nil, incorrect
+				}
+			} else if step == 1 {
+				to = from
+			}
+		}
+
+		if from < min || to > max || from > to {
+			return nil, incorrect
+		}
+
+		for value := from; value <= to; value += step {
+			set[value] = true
+		}
+	}
+
+	return set, nil
+}
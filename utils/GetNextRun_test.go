@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"testing"
+	"time"
+)
+
+func TestGetNextRun(t *testing.T) {
+	// a Monday
+	after := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		cron string
+		next time.Time
+		err  string
+	}{
+		{cron: "30 10 * * *", next: time.Date(2026, 10, 19, 10, 30, 0, 0, time.UTC)},
+		{cron: "0 10 * * *", next: time.Date(2026, 10, 20, 10, 0, 0, 0, time.UTC)},
+		{cron: "0 9 * * *", next: time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)},
+		{cron: "*/15 * * * *", next: time.Date(2026, 10, 19, 10, 15, 0, 0, time.UTC)},
+		{cron: "0 9 * * 5", next: time.Date(2026, 10, 23, 9, 0, 0, 0, time.UTC)},
+		{cron: "0 9 * * 7", next: time.Date(2026, 10, 25, 9, 0, 0, 0, time.UTC)},
+		{cron: "0 0 1 * *", next: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{cron: "0 12 31 * *", next: time.Date(2026, 10, 31, 12, 0, 0, 0, time.UTC)},
+		{cron: "0 12 1 1 *", next: time.Date(2027, 1, 1, 12, 0, 0, 0, time.UTC)},
+		{cron: "0 9 29 2 *", next: time.Date(2028, 2, 29, 9, 0, 0, 0, time.UTC)},
+		{cron: "0 9 13 * 5", next: time.Date(2026, 10, 23, 9, 0, 0, 0, time.UTC)},
+		{cron: "0 9 31 2 *", err: enums.UserErrors[enums.INCORRECT_SCHEDULE]},
+		{cron: "0 24 * * *", err: enums.UserErrors[enums.INCORRECT_SCHEDULE]},
+		{cron: "0 9 * *", err: enums.UserErrors[enums.INCORRECT_SCHEDULE]},
+		{cron: "0 9 5-1 * *", err: enums.UserErrors[enums.INCORRECT_SCHEDULE]},
+		{cron: "*/0 * * * *", err: enums.UserErrors[enums.INCORRECT_SCHEDULE]},
+	}
+
+	for _, test := range tests {
+		next, err := GetNextRun(test.cron, after)
+
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("GetNextRun(%q) error = %v, want %q", test.cron, err, test.err)
+			}
+
+			continue
+		}
+
+		if err != nil || !next.Equal(test.next) {
+			t.Errorf("GetNextRun(%q) = %v, %v, want %v", test.cron, next, err, test.next)
+		}
+	}
+}
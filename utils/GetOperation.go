@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func GetOperation(ctx context.Context, db *models.DataBase, account int, id string) (*models.Operation, error) {
+	var operation *models.Operation
+
+	// operations in shared banks belong to the owner of the bank, but their authors can still manage them
+	err := db.GetDocument(ctx, "operations", bson.M{
+		"$or": bson.A{
+			bson.M{"account": account},
+			bson.M{"author.id": account},
+		},
+		"id": id,
+	}).Decode(&operation)
+	if err != nil {
+		if err.Error() == "mongo: no documents in result" {
+			return nil, errors.New(enums.UserErrors[enums.OPERATION_NOT_FOUND])
+		} else {
+			return nil, err
+		}
+	}
+
+	return operation, nil
+}
@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+func GetOperationDescription(ctx context.Context, db *models.DataBase, operation *models.Operation) string {
+	bankName := "?"
+	if bank, err := GetBankById(ctx, db, operation.Account, operation.Bank); err == nil {
+		bankName = bank.Name
+	}
+
+	name := enums.OperationNames[enums.INCOME]
+	if operation.Operation == enums.BotCommands[enums.EXPENSE] {
+		name = enums.OperationNames[enums.EXPENSE]
+	}
+
+	if operation.Transfer != "" {
+		name = enums.OperationNames[enums.CREATE_TRANSFER] + " (" + name + ")"
+	}
+
+	description := "Операция от " + ParseCreatedAt(operation.CreatedAt).In(time.Local).Format("02.01.2006 15:04") +
+		"%0AТип: " + name +
+		"%0AКопилка: " + bankName +
+		"%0AСумма: " + strconv.Itoa(operation.Amount) + " руб."
+
+	if operation.Comment != "" {
+		description += "%0AКомментарий: " + url.QueryEscape(operation.Comment)
+	}
+
+	if operation.Author != nil {
+		description += "%0AАвтор: " + url.QueryEscape(operation.Author.Name())
+	}
+
+	if operation.Pending {
+		description += "%0AОжидает одобрения"
+	}
+
+	if operation.Reverted {
+		description += "%0AОперация отменена"
+	}
+
+	if len(operation.Edits) > 0 {
+		description += "%0A%0AИстория изменений:"
+
+		for _, edit := range operation.Edits {
+			field := edit.Field
+
+			for key, value := range enums.OperationFields {
+				if value == edit.Field {
+					field = enums.OperationFieldNames[key]
+				}
+			}
+
+			description += "%0A" + ParseCreatedAt(edit.EditedAt).In(time.Local).Format("02.01.2006 15:04") + " · " +
+				url.QueryEscape(field+": "+edit.Old+" → "+edit.New)
+		}
+	}
+
+	return description
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func GetOperationsFilter(account int, filter models.Filter) bson.M {
+	// pending operations don't exist for the balance until they are approved
+	query := bson.M{
+		"account": account,
+		"pending": bson.M{"$ne": true},
+	}
+
+	if filter.Bank != "" {
+		query["bank"] = filter.Bank
+	}
+
+	switch filter.Type {
+	case enums.BotCommands[enums.INCOME], enums.BotCommands[enums.EXPENSE]:
+		query["operation"] = filter.Type
+		query["transfer"] = bson.M{"$exists": false}
+	case enums.BotCommands[enums.CREATE_TRANSFER]:
+		query["transfer"] = bson.M{"$exists": true}
+	}
+
+	createdAt := bson.M{}
+	if !filter.From.IsZero() {
+		createdAt["$gte"] = filter.From.In(time.Local).Format("2006-01-02 15:04:05")
+	}
+	if !filter.To.IsZero() {
+		createdAt["$lt"] = filter.To.In(time.Local).Format("2006-01-02 15:04:05")
+	}
+	if len(createdAt) > 0 {
+		query["created_at"] = createdAt
+	}
+
+	return query
+}
@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+)
+
+func GetOverdraftCover(ctx context.Context, db *models.DataBase, bank *models.Bank, amount int) (*models.Bank, int, error) {
+	shortfall := amount - bank.Balance
+	if shortfall <= 0 || bank.Backup == "" || bank.Backup == bank.Id {
+		return nil, shortfall, nil
+	}
+
+	backup, err := GetBankById(ctx, db, bank.Account, bank.Backup)
+	if err != nil {
+		if err.Error() == enums.UserErrors[enums.BANK_NOT_FOUND] {
+			return nil, shortfall, nil
+		}
+
+		return nil, shortfall, err
+	}
+
+	if backup.Balance < shortfall {
+		return nil, shortfall, nil
+	}
+
+	return backup, shortfall, nil
+}
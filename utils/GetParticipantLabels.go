@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"BIEAS_bot/models"
+	"strconv"
+	"strings"
+)
+
+func GetParticipantLabels(participants []models.Participant) map[string]string {
+	names := map[string]map[string]bool{}
+	for _, participant := range participants {
+		name := strings.ToLower(participant.Name)
+		if names[name] == nil {
+			names[name] = map[string]bool{}
+		}
+
+		names[name][participant.Key()] = true
+	}
+
+	// members who share a name are told apart by the username or, without one, by the user id
+	labels := map[string]string{}
+	for _, participant := range participants {
+		label := participant.Name
+
+		if len(names[strings.ToLower(participant.Name)]) > 1 && participant.User != 0 {
+			if participant.Username != "" {
+				label += " (@" + participant.Username + ")"
+			} else {
+				label += " (id " + strconv.Itoa(participant.User) + ")"
+			}
+		}
+
+		labels[participant.Key()] = label
+	}
+
+	return labels
+}
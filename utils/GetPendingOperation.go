@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func GetPendingOperation(ctx context.Context, db *models.DataBase, id string) (*models.Operation, error) {
+	var operation *models.Operation
+
+	err := db.GetDocument(ctx, "operations", bson.M{
+		"id":      id,
+		"pending": true,
+	}).Decode(&operation)
+	if err != nil {
+		if err.Error() == "mongo: no documents in result" {
+			return nil, errors.New(enums.UserErrors[enums.REQUEST_NOT_FOUND])
+		} else {
+			return nil, err
+		}
+	}
+
+	return operation, nil
+}
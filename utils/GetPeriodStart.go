@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"time"
+)
+
+func GetPeriodStart(period string, now time.Time) time.Time {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	if period == enums.Periods[enums.DAY] {
+		return day
+	}
+
+	if period == enums.Periods[enums.WEEK] {
+		// weeks start on Monday
+		weekday := (int(day.Weekday()) + 6) % 7
+
+		return day.AddDate(0, 0, -weekday)
+	}
+
+	return day.AddDate(0, 0, 1-day.Day())
+}
@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func GetReceiptOperation(ctx context.Context, db *models.DataBase, account int, text string) (*models.Operation, error) {
+	operation, err := ParseReceipt(account, text)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := db.Collections["operations"].CountDocuments(ctx, bson.M{
+		"account":    account,
+		"receipt.fn": operation.Receipt.FN,
+		"receipt.fd": operation.Receipt.FD,
+		"receipt.fp": operation.Receipt.FP,
+		"reverted":   bson.M{"$ne": true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if count > 0 {
+		return nil, errors.New(enums.UserErrors[enums.RECEIPT_IS_IMPORTED])
+	}
+
+	return operation, nil
+}
@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"BIEAS_bot/models"
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func GetReport(ctx context.Context, db *models.DataBase, account int, from time.Time, to time.Time) (string, error) {
+	banks, err := GetBanks(ctx, db, account)
+	if err != nil {
+		return "", err
+	}
+
+	current, err := GetTotals(ctx, db, account, from, to)
+	if err != nil {
+		return "", err
+	}
+
+	// the previous period is the previous calendar month for a month and the same number of days otherwise
+	previousFrom := from.Add(-to.Sub(from))
+	if from.Day() == 1 && to.Equal(from.AddDate(0, 1, 0)) {
+		previousFrom = from.AddDate(0, -1, 0)
+	}
+
+	previous, err := GetTotals(ctx, db, account, previousFrom, from)
+	if err != nil {
+		return "", err
+	}
+
+	report := "Отчёт за " + from.Format("02.01.2006") + " – " + to.AddDate(0, 0, -1).Format("02.01.2006") + "%0A%0A" +
+		"Доходы: " + strconv.Itoa(current.Income) + " руб." + getComparison(current.Income, previous.Income) + "%0A" +
+		"Расходы: " + strconv.Itoa(current.Expense) + " руб." + getComparison(current.Expense, previous.Expense) + "%0A" +
+		"Изменение: " + formatSigned(current.Income-current.Expense) + " руб." +
+		getComparison(current.Income-current.Expense, previous.Income-previous.Expense) + "%0A"
+
+	report += "%0AПо копилкам:%0A" + getBankTotals(banks, current, "", 0)
+
+	if len(current.Banks) < 1 {
+		report += "Операций за этот период не было%0A"
+	}
+
+	report += "%0AВ скобках — сравнение с периодом " + previousFrom.Format("02.01.2006") + " – " +
+		from.AddDate(0, 0, -1).Format("02.01.2006")
+
+	return report, nil
+}
+
+// every bank is reported with the totals of its nested banks, nested banks are indented under their parent
+func getBankTotals(banks []models.Bank, report *models.Report, parent string, depth int) string {
+	var text string
+
+	indent := strings.Repeat("    ", depth)
+
+	for _, bank := range banks {
+		if bank.Parent != parent {
+			continue
+		}
+
+		totals := models.Totals{}
+		found := false
+
+		for _, subtree := range append([]models.Bank{bank}, GetDescendants(banks, bank.Id)...) {
+			if bankTotals, ok := report.Banks[subtree.Id]; ok {
+				totals.Income += bankTotals.Income
+				totals.Expense += bankTotals.Expense
+				totals.TransferIn += bankTotals.TransferIn
+				totals.TransferOut += bankTotals.TransferOut
+				found = true
+			}
+		}
+
+		if !found {
+			continue
+		}
+
+		text += indent + url.QueryEscape(bank.Name) + ": " + formatSigned(totals.Net()) + " руб.%0A" +
+			indent + "  доходы " + strconv.Itoa(totals.Income) + ", расходы " + strconv.Itoa(totals.Expense)
+
+		if totals.TransferIn > 0 || totals.TransferOut > 0 {
+			text += ", переводы " + formatSigned(totals.TransferIn) + "/-" + strconv.Itoa(totals.TransferOut)
+		}
+
+		text += "%0A" + getBankTotals(banks, report, bank.Id, depth+1)
+	}
+
+	return text
+}
+
+func getComparison(current int, previous int) string {
+	if previous == 0 {
+		return " (" + formatSigned(current) + ")"
+	}
+
+	percent := (current - previous) * 100 / abs(previous)
+
+	return " (" + formatSigned(current-previous) + " руб., " + formatSigned(percent) + "%25)"
+}
+
+func formatSigned(value int) string {
+	if value > 0 {
+		return url.QueryEscape("+" + strconv.Itoa(value))
+	}
+
+	return strconv.Itoa(value)
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+
+	return value
+}
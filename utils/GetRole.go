@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+)
+
+func GetRole(bank *models.Bank, account int) enums.Role {
+	if bank.Account == account {
+		return enums.OWNER
+	}
+
+	for _, member := range bank.Members {
+		if member.Account != account {
+			continue
+		}
+
+		for role, name := range enums.Roles {
+			if role != enums.UndefinedRole && name == member.Role {
+				return role
+			}
+		}
+	}
+
+	return enums.UndefinedRole
+}
@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"net/url"
+	"strconv"
+)
+
+func GetScheduleDescription(ctx context.Context, db *models.DataBase, schedule *models.Schedule) string {
+	bankName, targetName := "?", "?"
+
+	if bank, err := GetBankById(ctx, db, schedule.Account, schedule.Bank); err == nil {
+		bankName = bank.Name
+	}
+
+	if schedule.Target != "" {
+		if target, err := GetBankById(ctx, db, schedule.Account, schedule.Target); err == nil {
+			targetName = target.Name
+		}
+	}
+
+	var description string
+
+	switch schedule.Operation {
+	case enums.BotCommands[enums.INCOME]:
+		description = "Доход " + strconv.Itoa(schedule.Amount) + " руб. в копилку " + bankName
+	case enums.BotCommands[enums.EXPENSE]:
+		description = "Расход " + strconv.Itoa(schedule.Amount) + " руб. из копилки " + bankName
+	case enums.BotCommands[enums.CREATE_TRANSFER]:
+		description = "Перевод " + strconv.Itoa(schedule.Amount) + " руб. из копилки " + bankName +
+			" в копилку " + targetName
+	}
+
+	if schedule.Comment != "" {
+		description += " (" + url.QueryEscape(schedule.Comment) + ")"
+	}
+
+	description += "%0AРасписание: " + schedule.Rule
+
+	if schedule.Paused {
+		description += "%0AНа паузе"
+	} else {
+		description += "%0AСледующее выполнение: " + schedule.NextRun.Format("02.01.2006 15:04")
+	}
+
+	return description
+}
@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"errors"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func GetSchedules(ctx context.Context, db *models.DataBase, account int) ([]models.Schedule, []string, error) {
+	var schedules []models.Schedule
+	var labels []string
+
+	documents, err := db.GetDocuments(ctx, "schedules", bson.M{"account": account})
+	if err != nil {
+		return nil, nil, errors.New(enums.UserErrors[enums.UNEXPECTED_ERROR])
+	}
+	defer documents.Close(ctx)
+
+	if err = documents.All(ctx, &schedules); err != nil {
+		return nil, nil, errors.New(enums.UserErrors[enums.UNEXPECTED_ERROR])
+	}
+
+	if len(schedules) < 1 {
+		return nil, nil, errors.New(enums.UserErrors[enums.NO_SCHEDULES])
+	}
+
+	for index, schedule := range schedules {
+		var name string
+
+		for command, operation := range enums.OperationNames {
+			if enums.BotCommands[command] == schedule.Operation {
+				name = operation
+			}
+		}
+
+		label := strconv.Itoa(index+1) + ". " + name + " " + strconv.Itoa(schedule.Amount) + " руб."
+		if schedule.Comment != "" {
+			label += " (" + schedule.Comment + ")"
+		}
+
+		labels = append(labels, label)
+	}
+
+	return schedules, labels, nil
+}
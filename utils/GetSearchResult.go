@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+func GetSearchResult(ctx context.Context, db *models.DataBase, account int, text string) (string, error) {
+	operations, err := SearchOperations(ctx, db, account, text)
+	if err != nil {
+		return "", err
+	}
+
+	if len(operations) < 1 {
+		return "По запросу «" + url.QueryEscape(text) + "» ничего не найдено", nil
+	}
+
+	bankNames := make(map[string]string)
+
+	banks, err := GetBanks(ctx, db, account)
+	if err != nil && err.Error() != enums.UserErrors[enums.NO_BANKS] {
+		return "", err
+	}
+
+	for _, bank := range banks {
+		bankNames[bank.Id] = url.QueryEscape(bank.Name)
+	}
+
+	result := "Найдено операций: " + strconv.Itoa(len(operations)) + "%0A%0A"
+
+	var income, expense int
+
+	for index, operation := range operations {
+		sign := "+"
+		if operation.Operation == enums.BotCommands[enums.EXPENSE] {
+			sign = "-"
+			expense += operation.Amount
+		} else {
+			income += operation.Amount
+		}
+
+		if index == 20 {
+			result += "…и ещё " + strconv.Itoa(len(operations)-index) + "%0A"
+		}
+
+		if index >= 20 {
+			continue
+		}
+
+		result += ParseCreatedAt(operation.CreatedAt).In(time.Local).Format("02.01.2006") + " · " +
+			bankNames[operation.Bank] + " · " + url.QueryEscape(sign) + strconv.Itoa(operation.Amount) + " руб. · " +
+			url.QueryEscape(operation.Comment) + "%0A"
+	}
+
+	result += "%0AСумма расходов: " + strconv.Itoa(expense) + " руб.%0A" +
+		"Сумма доходов: " + strconv.Itoa(income) + " руб."
+
+	return result, nil
+}
@@ -0,0 +1,19 @@
+package utils
+
+import (
+	"BIEAS_bot/models"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func GetSettings(ctx context.Context, db *models.DataBase, account int) (*models.Settings, error) {
+	settings := &models.Settings{Account: account}
+
+	err := db.GetDocument(ctx, "settings", bson.M{"account": account}).Decode(settings)
+	if err != nil && err.Error() != "mongo: no documents in result" {
+		return nil, err
+	}
+
+	return settings, nil
+}
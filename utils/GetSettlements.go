@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"BIEAS_bot/models"
+	"sort"
+)
+
+func GetSettlements(balances []models.SplitBalance) []models.Settlement {
+	var creditors, debtors []models.SplitBalance
+
+	for _, balance := range balances {
+		if balance.Balance > 0 {
+			creditors = append(creditors, balance)
+		} else if balance.Balance < 0 {
+			debtors = append(debtors, models.SplitBalance{
+				Participant: balance.Participant,
+				Balance:     -balance.Balance,
+			})
+		}
+	}
+
+	// the biggest debt is always paid to the biggest creditor, so every transfer closes at least one of them
+	// and there are fewer transfers than members with a non-zero balance
+	byBalance := func(items []models.SplitBalance) func(i, j int) bool {
+		return func(i, j int) bool {
+			if items[i].Balance != items[j].Balance {
+				return items[i].Balance > items[j].Balance
+			}
+
+			return items[i].Participant.Key() < items[j].Participant.Key()
+		}
+	}
+
+	var settlements []models.Settlement
+
+	for len(creditors) > 0 && len(debtors) > 0 {
+		sort.Slice(creditors, byBalance(creditors))
+		sort.Slice(debtors, byBalance(debtors))
+
+		amount := creditors[0].Balance
+		if debtors[0].Balance < amount {
+			amount = debtors[0].Balance
+		}
+
+		settlements = append(settlements, models.Settlement{
+			From:   debtors[0].Participant,
+			To:     creditors[0].Participant,
+			Amount: amount,
+		})
+
+		creditors[0].Balance -= amount
+		debtors[0].Balance -= amount
+
+		if creditors[0].Balance == 0 {
+			creditors = creditors[1:]
+		}
+		if debtors[0].Balance == 0 {
+			debtors = debtors[1:]
+		}
+	}
+
+	return settlements
+}
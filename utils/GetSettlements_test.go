@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"BIEAS_bot/models"
+	"testing"
+)
+
+func TestGetSettlements(t *testing.T) {
+	anna := models.Participant{User: 1, Name: "Аня"}
+	boris := models.Participant{User: 2, Name: "Борис"}
+	sasha := models.Participant{User: 3, Name: "Саша"}
+	namesake := models.Participant{User: 4, Name: "Саша"}
+	guest := models.Participant{Name: "Гость"}
+
+	tests := []struct {
+		name        string
+		balances    []models.SplitBalance
+		settlements []models.Settlement
+	}{
+		{
+			name:     "everyone is settled",
+			balances: []models.SplitBalance{{Participant: anna}, {Participant: boris}},
+		},
+		{
+			name:        "one debtor",
+			balances:    []models.SplitBalance{{Participant: anna, Balance: 500}, {Participant: boris, Balance: -500}},
+			settlements: []models.Settlement{{From: boris, To: anna, Amount: 500}},
+		},
+		{
+			name: "one creditor and several debtors",
+			balances: []models.SplitBalance{
+				{Participant: anna, Balance: 900},
+				{Participant: boris, Balance: -300},
+				{Participant: sasha, Balance: -400},
+				{Participant: guest, Balance: -200},
+			},
+			settlements: []models.Settlement{
+				{From: sasha, To: anna, Amount: 400},
+				{From: boris, To: anna, Amount: 300},
+				{From: guest, To: anna, Amount: 200},
+			},
+		},
+		{
+			name: "the biggest debt goes to the biggest creditor",
+			balances: []models.SplitBalance{
+				{Participant: anna, Balance: 700},
+				{Participant: boris, Balance: 300},
+				{Participant: sasha, Balance: -600},
+				{Participant: namesake, Balance: -400},
+			},
+			settlements: []models.Settlement{
+				{From: sasha, To: anna, Amount: 600},
+				{From: namesake, To: boris, Amount: 300},
+				{From: namesake, To: anna, Amount: 100},
+			},
+		},
+		{
+			name: "members with the same name stay apart",
+			balances: []models.SplitBalance{
+				{Participant: sasha, Balance: 250},
+				{Participant: namesake, Balance: -250},
+			},
+			settlements: []models.Settlement{{From: namesake, To: sasha, Amount: 250}},
+		},
+	}
+
+	for _, test := range tests {
+		settlements := GetSettlements(test.balances)
+
+		if len(settlements) != len(test.settlements) {
+			t.Errorf("%s: GetSettlements() = %+v, want %+v", test.name, settlements, test.settlements)
+
+			continue
+		}
+
+		for index := range settlements {
+			if settlements[index] != test.settlements[index] {
+				t.Errorf("%s: GetSettlements() = %+v, want %+v", test.name, settlements, test.settlements)
+
+				break
+			}
+		}
+
+		// the transfers must bring every balance to zero
+		totals := map[string]int{}
+		for _, balance := range test.balances {
+			totals[balance.Participant.Key()] = balance.Balance
+		}
+
+		for _, settlement := range settlements {
+			totals[settlement.From.Key()] += settlement.Amount
+			totals[settlement.To.Key()] -= settlement.Amount
+		}
+
+		for key, total := range totals {
+			if total != 0 {
+				t.Errorf("%s: balance of %s is %d after the transfers", test.name, key, total)
+			}
+		}
+	}
+}
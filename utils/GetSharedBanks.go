@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func GetSharedBanks(ctx context.Context, db *models.DataBase, account int) ([]models.Bank, error) {
+	var banks []models.Bank
+
+	documents, err := db.GetDocuments(ctx, "banks", bson.M{"members.account": account})
+	if err != nil {
+		return nil, errors.New(enums.UserErrors[enums.UNEXPECTED_ERROR])
+	}
+	defer documents.Close(ctx)
+
+	if err = documents.All(ctx, &banks); err != nil {
+		return nil, errors.New(enums.UserErrors[enums.UNEXPECTED_ERROR])
+	}
+
+	return banks, nil
+}
@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func GetSpent(ctx context.Context, db *models.DataBase, bank *models.Bank, from time.Time) (int, error) {
+	documents, err := db.Aggregate(ctx, "operations", []bson.M{
+		{"$match": bson.M{
+			"bank":       bank.Id,
+			"operation":  enums.BotCommands[enums.EXPENSE],
+			"transfer":   bson.M{"$exists": false},
+			"reverted":   bson.M{"$ne": true},
+			"reverts":    bson.M{"$exists": false},
+			"pending":    bson.M{"$ne": true},
+			"created_at": bson.M{"$gte": from.In(time.Local).Format("2006-01-02 15:04:05")},
+		}},
+		{"$group": bson.M{
+			"_id":   nil,
+			"spent": bson.M{"$sum": "$amount"},
+		}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer documents.Close(ctx)
+
+	var result []struct {
+		Spent int `bson:"spent"`
+	}
+
+	if err = documents.All(ctx, &result); err != nil {
+		return 0, err
+	}
+
+	if len(result) < 1 {
+		return 0, nil
+	}
+
+	return result[0].Spent, nil
+}
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"errors"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func GetSplitBalances(ctx context.Context, db *models.DataBase, account int) ([]models.SplitBalance, error) {
+	var splits []models.Split
+
+	documents, err := db.GetDocuments(ctx, "splits", bson.M{
+		"account":  account,
+		"proposed": bson.M{"$ne": true},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer documents.Close(ctx)
+
+	if err = documents.All(ctx, &splits); err != nil {
+		return nil, err
+	}
+
+	if len(splits) < 1 {
+		return nil, errors.New(enums.UserErrors[enums.NO_SPLITS])
+	}
+
+	// the payer is owed the whole amount and every participant owes their share of it,
+	// and a member is shown by the name from their latest split
+	totals := map[string]*models.SplitBalance{}
+	change := func(participant models.Participant, amount int) {
+		key := participant.Key()
+		if totals[key] == nil {
+			totals[key] = &models.SplitBalance{}
+		}
+
+		totals[key].Participant = participant
+		totals[key].Balance += amount
+	}
+
+	for _, split := range splits {
+		change(split.Payer, split.Amount)
+
+		for _, share := range split.Shares {
+			change(share.Participant, -share.Amount)
+		}
+	}
+
+	var balances []models.SplitBalance
+	for _, balance := range totals {
+		balances = append(balances, *balance)
+	}
+
+	sort.Slice(balances, func(i, j int) bool {
+		if balances[i].Participant.Name != balances[j].Participant.Name {
+			return balances[i].Participant.Name < balances[j].Participant.Name
+		}
+
+		return balances[i].Participant.Key() < balances[j].Participant.Key()
+	})
+
+	return balances, nil
+}
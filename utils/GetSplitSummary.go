@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"BIEAS_bot/models"
+	"net/url"
+	"strconv"
+)
+
+func GetSplitSummary(balances []models.SplitBalance) string {
+	var summary string
+
+	var participants []models.Participant
+	for _, balance := range balances {
+		participants = append(participants, balance.Participant)
+	}
+
+	labels := GetParticipantLabels(participants)
+
+	for _, balance := range balances {
+		sign := ""
+		if balance.Balance > 0 {
+			sign = "+"
+		}
+
+		summary += "%0A" + url.QueryEscape(labels[balance.Participant.Key()]+": "+sign) + strconv.Itoa(balance.Balance) + " руб."
+	}
+
+	return summary
+}
@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"errors"
+)
+
+func GetStatementImport(ctx context.Context, db *models.DataBase, account int, records [][]string, columns models.Columns) ([]models.Operation, string, error) {
+	operations, err := ParseStatement(account, records, columns)
+	if err != nil {
+		return nil, "", err
+	}
+
+	operations, skipped, err := SkipImported(ctx, db, account, operations)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(operations) < 1 {
+		return nil, "", errors.New(enums.UserErrors[enums.STATEMENT_IS_IMPORTED])
+	}
+
+	return operations, GetStatementPreview(operations, skipped), nil
+}
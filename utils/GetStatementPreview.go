@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"BIEAS_bot/models"
+	"strconv"
+)
+
+func GetStatementPreview(operations []models.Operation, skipped int) string {
+	preview := "Операций в выписке: " + strconv.Itoa(len(operations)+skipped)
+	if skipped > 0 {
+		preview += ", из них уже добавлены и будут пропущены: " + strconv.Itoa(skipped)
+	}
+
+	preview += "%0A%0A"
+
+	for index, operation := range operations {
+		if index == 5 {
+			preview += "…и ещё " + strconv.Itoa(len(operations)-index) + "%0A"
+
+			break
+		}
+
+		preview += GetStatementRow(operation) + "%0A"
+	}
+
+	return preview
+}
@@ -0,0 +1,18 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"net/url"
+	"strconv"
+)
+
+func GetStatementRow(operation models.Operation) string {
+	sign := "+"
+	if operation.Operation == enums.BotCommands[enums.EXPENSE] {
+		sign = "-"
+	}
+
+	return ParseCreatedAt(operation.CreatedAt).Format("02.01.2006") + " · " + url.QueryEscape(sign) +
+		strconv.Itoa(operation.Amount) + " руб. · " + url.QueryEscape(operation.Comment)
+}
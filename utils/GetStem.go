@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// endings are ordered from the longest, so that the longest matching one is stripped
+var endings = []string{
+	"иями", "ями", "ами", "ого", "его", "ому", "ему", "ыми", "ими", "ешь", "ишь",
+	"ая", "яя", "ое", "ее", "ые", "ие", "ой", "ей", "ий", "ый", "ом", "ем", "ам", "ям", "ах", "ях",
+	"ов", "ев", "ию", "ью", "ия", "ья", "ть", "ет", "ют", "ут", "ит", "ат", "ят",
+	"а", "я", "о", "е", "и", "ы", "у", "ю", "ь", "й",
+}
+
+func GetStem(word string) string {
+	word = strings.ReplaceAll(strings.ToLower(word), "ё", "е")
+
+	for _, ending := range endings {
+		if strings.HasSuffix(word, ending) && utf8.RuneCountInString(word)-utf8.RuneCountInString(ending) >= 3 {
+			return strings.TrimSuffix(word, ending)
+		}
+	}
+
+	return word
+}
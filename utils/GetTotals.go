@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func GetTotals(ctx context.Context, db *models.DataBase, account int, from time.Time, to time.Time) (*models.Report, error) {
+	documents, err := db.Aggregate(ctx, "operations", []bson.M{
+		{"$match": bson.M{
+			"account": account,
+			"created_at": bson.M{
+				"$gte": from.In(time.Local).Format("2006-01-02 15:04:05"),
+				"$lt":  to.In(time.Local).Format("2006-01-02 15:04:05"),
+			},
+			"reverted": bson.M{"$ne": true},
+			"reverts":  bson.M{"$exists": false},
+			"pending":  bson.M{"$ne": true},
+		}},
+		{"$group": bson.M{
+			"_id": bson.M{
+				"bank":      "$bank",
+				"operation": "$operation",
+				"transfer":  bson.M{"$gt": bson.A{"$transfer", nil}},
+			},
+			"amount": bson.M{"$sum": "$amount"},
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer documents.Close(ctx)
+
+	var groups []struct {
+		Id struct {
+			Bank      string `bson:"bank"`
+			Operation string `bson:"operation"`
+			Transfer  bool   `bson:"transfer"`
+		} `bson:"_id"`
+		Amount int `bson:"amount"`
+	}
+
+	if err = documents.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+
+	report := &models.Report{Banks: make(map[string]*models.Totals)}
+
+	for _, group := range groups {
+		totals, ok := report.Banks[group.Id.Bank]
+		if !ok {
+			totals = &models.Totals{}
+			report.Banks[group.Id.Bank] = totals
+		}
+
+		income := group.Id.Operation == enums.BotCommands[enums.INCOME]
+
+		switch {
+		case group.Id.Transfer && income:
+			totals.TransferIn += group.Amount
+		case group.Id.Transfer:
+			totals.TransferOut += group.Amount
+		case income:
+			totals.Income += group.Amount
+			report.Income += group.Amount
+		default:
+			totals.Expense += group.Amount
+			report.Expense += group.Amount
+		}
+	}
+
+	return report, nil
+}
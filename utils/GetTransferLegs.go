@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"BIEAS_bot/models"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func GetTransferLegs(ctx context.Context, db *models.DataBase, transfer string, except string) ([]models.Operation, error) {
+	var legs []models.Operation
+
+	if transfer == "" {
+		return legs, nil
+	}
+
+	// the legs are found by the transfer alone, as they belong to different accounts when banks are shared
+	documents, err := db.GetDocuments(ctx, "operations", bson.M{
+		"transfer": transfer,
+		"id":       bson.M{"$ne": except},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer documents.Close(ctx)
+
+	if err = documents.All(ctx, &legs); err != nil {
+		return nil, err
+	}
+
+	return legs, nil
+}
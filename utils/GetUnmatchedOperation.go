@@ -0,0 +1,13 @@
+package utils
+
+import "BIEAS_bot/models"
+
+func GetUnmatchedOperation(operations []models.Operation) int {
+	for index, operation := range operations {
+		if operation.Bank == "" {
+			return index
+		}
+	}
+
+	return -1
+}
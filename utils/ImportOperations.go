@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+func ImportOperations(ctx context.Context, db *models.DataBase, account int, operations []models.Operation, author *models.User) ([]*models.Bank, error) {
+	var documents []interface{}
+
+	changes := make(map[string]int)
+
+	for index := range operations {
+		id, err := gonanoid.New()
+		if err != nil {
+			return nil, err
+		}
+
+		operations[index].Id = id
+		operations[index].Author = author
+
+		documents = append(documents, operations[index])
+
+		if operations[index].Operation == enums.BotCommands[enums.EXPENSE] {
+			changes[operations[index].Bank] -= operations[index].Amount
+		} else {
+			changes[operations[index].Bank] += operations[index].Amount
+		}
+	}
+
+	if len(documents) < 1 {
+		return nil, nil
+	}
+
+	if _, err := db.Collections["operations"].InsertMany(ctx, documents); err != nil {
+		return nil, err
+	}
+
+	var banks []*models.Bank
+
+	for id, change := range changes {
+		bank, err := GetBankById(ctx, db, account, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = bank.Increase(ctx, db, change); err != nil {
+			return nil, err
+		}
+
+		banks = append(banks, bank)
+	}
+
+	return banks, nil
+}
@@ -0,0 +1,22 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"regexp"
+	"strings"
+)
+
+var quickAmount = regexp.MustCompile(`^[+-]\d+$`)
+
+func IsQuickEntry(text string) bool {
+	fields := strings.Fields(text)
+	if len(fields) < 1 {
+		return false
+	}
+
+	if fields[0] == enums.BotCommands[enums.INCOME] || fields[0] == enums.BotCommands[enums.EXPENSE] {
+		return len(fields) > 1
+	}
+
+	return quickAmount.MatchString(fields[0])
+}
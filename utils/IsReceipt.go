@@ -0,0 +1,12 @@
+package utils
+
+import "net/url"
+
+func IsReceipt(text string) bool {
+	values, err := url.ParseQuery(text)
+	if err != nil {
+		return false
+	}
+
+	return values.Get("fn") != "" && values.Get("fp") != "" && values.Get("s") != ""
+}
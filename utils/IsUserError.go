@@ -0,0 +1,13 @@
+package utils
+
+import "BIEAS_bot/enums"
+
+func IsUserError(err error) bool {
+	for _, userError := range enums.UserErrors {
+		if err.Error() == userError {
+			return true
+		}
+	}
+
+	return false
+}
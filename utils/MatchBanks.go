@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"BIEAS_bot/models"
+	"context"
+	"regexp"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func MatchBanks(ctx context.Context, db *models.DataBase, account int, operations []models.Operation) error {
+	banks, err := GetBanks(ctx, db, account)
+	if err != nil {
+		return err
+	}
+
+	exists := make(map[string]bool)
+	for _, bank := range banks {
+		exists[bank.Id] = true
+	}
+
+	known := make(map[string]string)
+
+	for index := range operations {
+		if operations[index].Bank != "" {
+			continue
+		}
+
+		comment := strings.ToLower(operations[index].Comment)
+
+		bank, ok := known[comment]
+		if !ok {
+			// the bank of the latest operation with the same comment is the best guess
+			var previous models.Operation
+
+			err = db.Collections["operations"].FindOne(
+				ctx,
+				bson.M{
+					"account":  account,
+					"comment":  bson.M{"$regex": "^" + regexp.QuoteMeta(operations[index].Comment) + "$", "$options": "i"},
+					"transfer": bson.M{"$exists": false},
+					"reverts":  bson.M{"$exists": false},
+				},
+				options.FindOne().SetSort(bson.M{"created_at": -1}),
+			).Decode(&previous)
+			if err == nil && exists[previous.Bank] {
+				bank = previous.Bank
+			} else if err != nil && err.Error() != "mongo: no documents in result" {
+				return err
+			} else if found := FindBank(banks, operations[index].Comment); found != nil {
+				bank = found.Id
+			}
+
+			known[comment] = bank
+		}
+
+		operations[index].Bank = bank
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func MergeBanks(ctx context.Context, db *models.DataBase, from *models.Bank, to *models.Bank) error {
+	if from.Id == to.Id {
+		return errors.New(enums.UserErrors[enums.SAME_BANK])
+	}
+
+	// transfer legs of other banks should point to the bank which is left
+	if err := UpdateTransferComments(ctx, db, from, from.Name, to.Name); err != nil {
+		return err
+	}
+
+	if _, err := db.Collections["operations"].UpdateMany(
+		ctx,
+		bson.M{
+			"account": from.Account,
+			"bank":    from.Id,
+		},
+		bson.M{
+			"$set": bson.M{"bank": to.Id},
+		},
+	); err != nil {
+		return err
+	}
+
+	for _, field := range []string{"bank", "target"} {
+		if _, err := db.Collections["schedules"].UpdateMany(
+			ctx,
+			bson.M{
+				"account": from.Account,
+				field:     from.Id,
+			},
+			bson.M{
+				"$set": bson.M{field: to.Id},
+			},
+		); err != nil {
+			return err
+		}
+	}
+
+	if _, err := db.Collections["banks"].UpdateMany(
+		ctx,
+		bson.M{
+			"account": from.Account,
+			"id":      bson.M{"$ne": to.Id},
+			"backup":  from.Id,
+		},
+		bson.M{
+			"$set": bson.M{"backup": to.Id},
+		},
+	); err != nil {
+		return err
+	}
+
+	if to.Backup == from.Id {
+		if err := to.Update(ctx, db, bson.M{"backup": ""}); err != nil {
+			return err
+		}
+	}
+
+	banks, err := GetBanks(ctx, db, from.Account)
+	if err != nil {
+		return err
+	}
+
+	// a nested bank takes the place of the merged one, so that the tree stays acyclic
+	for _, descendant := range GetDescendants(banks, from.Id) {
+		if descendant.Id == to.Id {
+			if err = to.Update(ctx, db, bson.M{"parent": from.Parent}); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := db.Collections["banks"].UpdateMany(
+		ctx,
+		bson.M{
+			"account": from.Account,
+			"id":      bson.M{"$ne": to.Id},
+			"parent":  from.Id,
+		},
+		bson.M{
+			"$set": bson.M{"parent": to.Id},
+		},
+	); err != nil {
+		return err
+	}
+
+	if err := to.Increase(ctx, db, from.Balance); err != nil {
+		return err
+	}
+
+	return from.Destroy(ctx, db)
+}
@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// maxAmount keeps the result of an expression far from the limits of int
+const maxAmount = 1e12
+
+type amountParser struct {
+	text     []rune
+	position int
+}
+
+func ParseAmount(text string) (int, bool, error) {
+	text = strings.ReplaceAll(strings.Join(strings.Fields(text), ""), ",", ".")
+	if text == "" || len(text) > 100 {
+		return 0, false, errors.New(enums.UserErrors[enums.INCORRECT_VALUE])
+	}
+
+	parser := &amountParser{text: []rune(text)}
+
+	value, err := parser.parseSum()
+	if err != nil || parser.position < len(parser.text) || math.IsInf(value, 0) || math.IsNaN(value) {
+		return 0, false, errors.New(enums.UserErrors[enums.INCORRECT_VALUE])
+	}
+
+	if value > maxAmount {
+		return 0, false, errors.New(enums.UserErrors[enums.AMOUNT_TOO_LARGE])
+	}
+
+	amount := int(math.Round(value))
+	if amount <= 0 {
+		return 0, false, errors.New(enums.UserErrors[enums.NON_POSITIVE_AMOUNT])
+	}
+
+	_, err = strconv.Atoi(text)
+
+	return amount, err != nil, nil
+}
+
+func (parser *amountParser) parseSum() (float64, error) {
+	value, err := parser.parseProduct()
+	if err != nil {
+		return 0, err
+	}
+
+	for parser.position < len(parser.text) {
+		operator := parser.text[parser.position]
+		if operator != '+' && operator != '-' {
+			break
+		}
+
+		parser.position++
+
+		operand, err := parser.parseProduct()
+		if err != nil {
+			return 0, err
+		}
+
+		if operator == '+' {
+			value += operand
+		} else {
+			value -= operand
+		}
+	}
+
+	return value, nil
+}
+
+func (parser *amountParser) parseProduct() (float64, error) {
+	value, err := parser.parseFactor()
+	if err != nil {
+		return 0, err
+	}
+
+	for parser.position < len(parser.text) {
+		operator := parser.text[parser.position]
+		if operator != '*' && operator != '/' {
+			break
+		}
+
+		parser.position++
+
+		operand, err := parser.parseFactor()
+		if err != nil {
+			return 0, err
+		}
+
+		if operator == '*' {
+			value *= operand
+		} else if operand == 0 {
+			return 0, errors.New(enums.UserErrors[enums.INCORRECT_VALUE])
+		} else {
+			value /= operand
+		}
+	}
+
+	return value, nil
+}
+
+func (parser *amountParser) parseFactor() (float64, error) {
+	if parser.position >= len(parser.text) {
+		return 0, errors.New(enums.UserErrors[enums.INCORRECT_VALUE])
+	}
+
+	switch parser.text[parser.position] {
+	case '-':
+		parser.position++
+
+		value, err := parser.parseFactor()
+
+		return -value, err
+	case '(':
+		parser.position++
+
+		value, err := parser.parseSum()
+		if err != nil {
+			return 0, err
+		}
+
+		if parser.position >= len(parser.text) || parser.text[parser.position] != ')' {
+			return 0, errors.New(enums.UserErrors[enums.INCORRECT_VALUE])
+		}
+
+		parser.position++
+
+		return value, nil
+	}
+
+	start := parser.position
+	for parser.position < len(parser.text) &&
+		(parser.text[parser.position] >= '0' && parser.text[parser.position] <= '9' || parser.text[parser.position] == '.') {
+		parser.position++
+	}
+
+	return strconv.ParseFloat(string(parser.text[start:parser.position]), 64)
+}
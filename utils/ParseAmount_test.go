@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"testing"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		text       string
+		amount     int
+		expression bool
+		err        string
+	}{
+		{text: "350", amount: 350},
+		{text: " 1 000 ", amount: 1000},
+		{text: "120+340+89", amount: 549, expression: true},
+		{text: "3*250", amount: 750, expression: true},
+		{text: "(2+3)*4", amount: 20, expression: true},
+		{text: "1000-250/2", amount: 875, expression: true},
+		{text: "10,5", amount: 11, expression: true},
+		{text: "99.4", amount: 99, expression: true},
+		{text: "-5", err: enums.UserErrors[enums.NON_POSITIVE_AMOUNT]},
+		{text: "5-5", err: enums.UserErrors[enums.NON_POSITIVE_AMOUNT]},
+		{text: "0.4", err: enums.UserErrors[enums.NON_POSITIVE_AMOUNT]},
+		{text: "1000000000001", err: enums.UserErrors[enums.AMOUNT_TOO_LARGE]},
+		{text: "99999999999*99999999999", err: enums.UserErrors[enums.AMOUNT_TOO_LARGE]},
+		{text: "1/0", err: enums.UserErrors[enums.INCORRECT_VALUE]},
+		{text: "(2+3", err: enums.UserErrors[enums.INCORRECT_VALUE]},
+		{text: "2+", err: enums.UserErrors[enums.INCORRECT_VALUE]},
+		{text: "сто", err: enums.UserErrors[enums.INCORRECT_VALUE]},
+		{text: "", err: enums.UserErrors[enums.INCORRECT_VALUE]},
+	}
+
+	for _, test := range tests {
+		amount, expression, err := ParseAmount(test.text)
+
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("ParseAmount(%q) error = %v, want %q", test.text, err, test.err)
+			}
+
+			continue
+		}
+
+		if err != nil || amount != test.amount || expression != test.expression {
+			t.Errorf("ParseAmount(%q) = %d, %v, %v, want %d, %v", test.text, amount, expression, err, test.amount, test.expression)
+		}
+	}
+}
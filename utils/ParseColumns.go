@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+func ParseColumns(text string, count int) (models.Columns, error) {
+	var numbers []int
+
+	for _, field := range strings.Fields(strings.ReplaceAll(text, ",", " ")) {
+		number, err := strconv.Atoi(field)
+		if err != nil || number < 1 || number > count {
+			return models.Columns{}, errors.New(enums.UserErrors[enums.INCORRECT_COLUMNS])
+		}
+
+		numbers = append(numbers, number-1)
+	}
+
+	if len(numbers) != 3 {
+		return models.Columns{}, errors.New(enums.UserErrors[enums.INCORRECT_COLUMNS])
+	}
+
+	return models.Columns{Date: numbers[0], Amount: numbers[1], Description: numbers[2]}, nil
+}
@@ -0,0 +1,20 @@
+package utils
+
+import (
+	"strings"
+	"time"
+)
+
+func ParseCreatedAt(createdAt string) time.Time {
+	// created_at is stored as time.Time.String(), which may carry a monotonic clock reading
+	if index := strings.Index(createdAt, " m="); index >= 0 {
+		createdAt = createdAt[:index]
+	}
+
+	date, err := time.Parse("2006-01-02 15:04:05.999999999 -0700 MST", createdAt)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return date
+}
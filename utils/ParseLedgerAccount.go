@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"errors"
+	"strings"
+)
+
+func ParseLedgerAccount(text string) (string, error) {
+	components := strings.Split(text, ":")
+	if len(components) < 2 {
+		return "", errors.New(enums.UserErrors[enums.INCORRECT_LEDGER_ACCOUNT])
+	}
+
+	for index, component := range components {
+		components[index] = strings.Join(strings.Fields(component), " ")
+
+		if components[index] == "" {
+			return "", errors.New(enums.UserErrors[enums.INCORRECT_LEDGER_ACCOUNT])
+		}
+	}
+
+	return strings.Join(components, ":"), nil
+}
@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"errors"
+	"strings"
+	"time"
+)
+
+func ParsePeriod(text string, now time.Time) (time.Time, time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	month := today.AddDate(0, 0, 1-today.Day())
+
+	switch text {
+	case enums.PeriodFilters[enums.ALL_TIME]:
+		return time.Time{}, time.Time{}, nil
+	case enums.PeriodFilters[enums.THIS_WEEK]:
+		return GetPeriodStart(enums.Periods[enums.WEEK], now), today.AddDate(0, 0, 1), nil
+	case enums.PeriodFilters[enums.THIS_MONTH]:
+		return month, month.AddDate(0, 1, 0), nil
+	case enums.PeriodFilters[enums.LAST_MONTH]:
+		return month.AddDate(0, -1, 0), month, nil
+	}
+
+	incorrect := errors.New(enums.UserErrors[enums.INCORRECT_PERIOD])
+
+	// a single month is written as ММ.ГГГГ
+	if from, err := time.ParseInLocation("01.2006", strings.TrimSpace(text), now.Location()); err == nil {
+		return from, from.AddDate(0, 1, 0), nil
+	}
+
+	dates := strings.SplitN(text, "-", 2)
+	if len(dates) != 2 {
+		return time.Time{}, time.Time{}, incorrect
+	}
+
+	from, err := time.ParseInLocation("02.01.2006", strings.TrimSpace(dates[0]), now.Location())
+	if err != nil {
+		return time.Time{}, time.Time{}, incorrect
+	}
+
+	to, err := time.ParseInLocation("02.01.2006", strings.TrimSpace(dates[1]), now.Location())
+	if err != nil || to.Before(from) {
+		return time.Time{}, time.Time{}, incorrect
+	}
+
+	return from, to.AddDate(0, 0, 1), nil
+}
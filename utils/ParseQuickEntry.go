@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+func ParseQuickEntry(ctx context.Context, db *models.DataBase, account int, text string) (*models.Operation, *models.Bank, error) {
+	if !IsQuickEntry(text) {
+		return nil, nil, errors.New(enums.UserErrors[enums.INCORRECT_VALUE])
+	}
+
+	banks, err := GetBanks(ctx, db, account)
+	if err != nil && err.Error() != enums.UserErrors[enums.NO_BANKS] {
+		return nil, nil, err
+	}
+
+	shared, err := GetSharedBanks(ctx, db, account)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	banks = append(banks, shared...)
+	if len(banks) < 1 {
+		return nil, nil, errors.New(enums.UserErrors[enums.NO_BANKS])
+	}
+
+	return parseQuickEntry(banks, account, text)
+}
+
+// parseQuickEntry splits the entry into the operation, the amount, the bank and the comment without the database
+func parseQuickEntry(banks []models.Bank, account int, text string) (*models.Operation, *models.Bank, error) {
+	var err error
+
+	fields := strings.Fields(text)
+	operation := &models.Operation{Account: account}
+
+	var bank *models.Bank
+
+	if fields[0] == enums.BotCommands[enums.INCOME] || fields[0] == enums.BotCommands[enums.EXPENSE] {
+		operation.Operation = fields[0]
+		fields = fields[1:]
+
+		// "/expense Еда 350 обед": the words before the amount are the bank and the words after it are the comment
+		position := -1
+		for index, field := range fields {
+			if amount, err := strconv.Atoi(field); err == nil && amount > 0 {
+				operation.Amount = amount
+				position = index
+
+				break
+			}
+		}
+
+		if position < 0 {
+			return operation, FindBank(banks, strings.Join(fields, " ")), nil
+		}
+
+		if position > 0 {
+			bank = FindBank(banks, strings.Join(fields[:position], " "))
+			operation.Comment = strings.Join(fields[position+1:], " ")
+
+			return operation, bank, nil
+		}
+
+		fields = fields[1:]
+	} else {
+		operation.Operation = enums.BotCommands[enums.INCOME]
+		if strings.HasPrefix(fields[0], "-") {
+			operation.Operation = enums.BotCommands[enums.EXPENSE]
+		}
+
+		if operation.Amount, err = strconv.Atoi(strings.TrimLeft(fields[0], "+-")); err != nil {
+			return nil, nil, errors.New(enums.UserErrors[enums.INCORRECT_VALUE])
+		}
+
+		fields = fields[1:]
+	}
+
+	// "-350 еда обед": the longest leading words that name a bank are the bank and the rest is the comment
+	for count := len(fields); count > 0; count-- {
+		if bank = FindBank(banks, strings.Join(fields[:count], " ")); bank != nil {
+			operation.Comment = strings.Join(fields[count:], " ")
+
+			return operation, bank, nil
+		}
+	}
+
+	operation.Comment = strings.Join(fields, " ")
+
+	return operation, nil, nil
+}
@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"testing"
+)
+
+func TestIsQuickEntry(t *testing.T) {
+	tests := map[string]bool{
+		"-350 еда обед":  true,
+		"+50000":         true,
+		"/expense Еда 1": true,
+		"/income":        false,
+		"350 еда":        false,
+		"+1 согласен":    true,
+		"-":              false,
+		"привет":         false,
+		"":               false,
+	}
+
+	for text, want := range tests {
+		if got := IsQuickEntry(text); got != want {
+			t.Errorf("IsQuickEntry(%q) = %v, want %v", text, got, want)
+		}
+	}
+}
+
+func TestParseQuickEntry(t *testing.T) {
+	banks := []models.Bank{
+		{Id: "food", Name: "Еда"},
+		{Id: "salary", Name: "Зарплата"},
+		{Id: "vacation", Name: "Отпуск 2026"},
+	}
+
+	income, expense := enums.BotCommands[enums.INCOME], enums.BotCommands[enums.EXPENSE]
+
+	tests := []struct {
+		text      string
+		operation string
+		amount    int
+		bank      string
+		comment   string
+		err       string
+	}{
+		{text: "-350 еда обед", operation: expense, amount: 350, bank: "food", comment: "обед"},
+		{text: "+50000 зарплата", operation: income, amount: 50000, bank: "salary"},
+		{text: "-1200 отпуск 2026 билеты", operation: expense, amount: 1200, bank: "vacation", comment: "билеты"},
+		{text: "-200 такси до дома", operation: expense, amount: 200, comment: "такси до дома"},
+		{text: "+10", operation: income, amount: 10},
+		{text: "/expense Еда 350 обед", operation: expense, amount: 350, bank: "food", comment: "обед"},
+		{text: "/income 500 подарок", operation: income, amount: 500, comment: "подарок"},
+		{text: "/income зарплата", operation: income, bank: "salary"},
+		{text: "-12abc еда", err: enums.UserErrors[enums.INCORRECT_VALUE]},
+	}
+
+	for _, test := range tests {
+		operation, bank, err := parseQuickEntry(banks, 1, test.text)
+
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("parseQuickEntry(%q) error = %v, want %q", test.text, err, test.err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("parseQuickEntry(%q) error = %v", test.text, err)
+
+			continue
+		}
+
+		id := ""
+		if bank != nil {
+			id = bank.Id
+		}
+
+		if operation.Operation != test.operation || operation.Amount != test.amount ||
+			id != test.bank || operation.Comment != test.comment || operation.Account != 1 {
+			t.Errorf(
+				"parseQuickEntry(%q) = %s %d %q %q, want %s %d %q %q",
+				test.text,
+				operation.Operation, operation.Amount, id, operation.Comment,
+				test.operation, test.amount, test.bank, test.comment,
+			)
+		}
+	}
+}
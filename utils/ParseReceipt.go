@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"errors"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func ParseReceipt(account int, text string) (*models.Operation, error) {
+	values, err := url.ParseQuery(strings.TrimSpace(text))
+	if err != nil {
+		return nil, errors.New(enums.UserErrors[enums.INCORRECT_RECEIPT])
+	}
+
+	sum, err := strconv.ParseFloat(values.Get("s"), 64)
+	if err != nil || sum <= 0 {
+		return nil, errors.New(enums.UserErrors[enums.INCORRECT_RECEIPT])
+	}
+
+	// the date is written as 20261018T1230 and some receipts also carry seconds
+	var date time.Time
+	for _, layout := range []string{"20060102T1504", "20060102T150405"} {
+		if date, err = time.ParseInLocation(layout, values.Get("t"), time.Local); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, errors.New(enums.UserErrors[enums.INCORRECT_RECEIPT])
+	}
+
+	receipt := &models.Receipt{
+		FN: values.Get("fn"),
+		FD: values.Get("i"),
+		FP: values.Get("fp"),
+	}
+
+	if receipt.FN == "" || receipt.FD == "" || receipt.FP == "" {
+		return nil, errors.New(enums.UserErrors[enums.INCORRECT_RECEIPT])
+	}
+
+	// only the receipts for a purchase are imported, returns are written as income manually
+	if values.Get("n") != "" && values.Get("n") != "1" {
+		return nil, errors.New(enums.UserErrors[enums.INCORRECT_RECEIPT])
+	}
+
+	return &models.Operation{
+		Account:   account,
+		Operation: enums.BotCommands[enums.EXPENSE],
+		Amount:    int(math.Round(sum)),
+		Receipt:   receipt,
+		CreatedAt: date.String(),
+	}, nil
+}
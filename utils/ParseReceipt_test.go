@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"testing"
+	"time"
+)
+
+func TestParseReceipt(t *testing.T) {
+	tests := []struct {
+		text    string
+		amount  int
+		date    time.Time
+		receipt models.Receipt
+		err     string
+	}{
+		{
+			text:    "t=20261018T1230&s=1234.50&fn=9960440300000000&i=12345&fp=987654321&n=1",
+			amount:  1235,
+			date:    time.Date(2026, 10, 18, 12, 30, 0, 0, time.Local),
+			receipt: models.Receipt{FN: "9960440300000000", FD: "12345", FP: "987654321"},
+		},
+		{
+			text:    " t=20261018T123015&s=99.40&fn=1&i=2&fp=3 ",
+			amount:  99,
+			date:    time.Date(2026, 10, 18, 12, 30, 15, 0, time.Local),
+			receipt: models.Receipt{FN: "1", FD: "2", FP: "3"},
+		},
+		{text: "t=20261018T1230&s=100&fn=1&i=2&fp=3&n=2", err: enums.UserErrors[enums.INCORRECT_RECEIPT]},
+		{text: "t=20261018T1230&s=100&fn=1&i=2", err: enums.UserErrors[enums.INCORRECT_RECEIPT]},
+		{text: "t=20261018T1230&s=0&fn=1&i=2&fp=3", err: enums.UserErrors[enums.INCORRECT_RECEIPT]},
+		{text: "t=18.10.2026&s=100&fn=1&i=2&fp=3", err: enums.UserErrors[enums.INCORRECT_RECEIPT]},
+		{text: "чек на 100 рублей", err: enums.UserErrors[enums.INCORRECT_RECEIPT]},
+	}
+
+	for _, test := range tests {
+		operation, err := ParseReceipt(1, test.text)
+
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("ParseReceipt(%q) error = %v, want %q", test.text, err, test.err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("ParseReceipt(%q) error = %v", test.text, err)
+
+			continue
+		}
+
+		if operation.Operation != enums.BotCommands[enums.EXPENSE] || operation.Amount != test.amount ||
+			operation.CreatedAt != test.date.String() || *operation.Receipt != test.receipt {
+			t.Errorf(
+				"ParseReceipt(%q) = %s %d %s %+v, want %d %s %+v",
+				test.text,
+				operation.Operation, operation.Amount, operation.CreatedAt, *operation.Receipt,
+				test.amount, test.date, test.receipt,
+			)
+		}
+	}
+}
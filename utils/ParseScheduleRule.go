@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var cronPattern = regexp.MustCompile(`^[0-9*,/-]+( [0-9*,/-]+){4}$`)
+var timePattern = regexp.MustCompile(`(\d{1,2}):(\d{2})`)
+var dayPattern = regexp.MustCompile(`\d{1,2}`)
+
+var weekdays = map[string]int{
+	"понедельник": 1,
+	"вторник":     2,
+	"сред":        3,
+	"четверг":     4,
+	"пятниц":      5,
+	"суббот":      6,
+	"воскресен":   0,
+}
+
+var weekdayAbbreviations = map[string]int{
+	"пн": 1,
+	"вт": 2,
+	"ср": 3,
+	"чт": 4,
+	"пт": 5,
+	"сб": 6,
+	"вс": 0,
+}
+
+func ParseScheduleRule(rule string) (string, error) {
+	rule = strings.ToLower(strings.Join(strings.Fields(rule), " "))
+
+	if cronPattern.MatchString(rule) {
+		if _, err := GetNextRun(rule, time.Now()); err != nil {
+			return "", err
+		}
+
+		return rule, nil
+	}
+
+	hour, minute := 9, 0
+	if match := timePattern.FindStringSubmatch(rule); match != nil {
+		hour, _ = strconv.Atoi(match[1])
+		minute, _ = strconv.Atoi(match[2])
+
+		if hour > 23 || minute > 59 {
+			return "", errors.New(enums.UserErrors[enums.INCORRECT_SCHEDULE])
+		}
+
+		rule = strings.Replace(rule, match[0], "", 1)
+	}
+
+	at := strconv.Itoa(minute) + " " + strconv.Itoa(hour) + " "
+
+	words := strings.FieldsFunc(rule, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for _, word := range words {
+		if weekday, ok := weekdayAbbreviations[word]; ok {
+			return at + "* * " + strconv.Itoa(weekday), nil
+		}
+
+		for prefix, weekday := range weekdays {
+			if strings.HasPrefix(word, prefix) {
+				return at + "* * " + strconv.Itoa(weekday), nil
+			}
+		}
+	}
+
+	if strings.Contains(rule, "месяц") || strings.Contains(rule, "месячн") || strings.Contains(rule, "числ") {
+		day, err := strconv.Atoi(dayPattern.FindString(rule))
+		if err != nil || day < 1 || day > 31 {
+			return "", errors.New(enums.UserErrors[enums.INCORRECT_SCHEDULE])
+		}
+
+		return at + strconv.Itoa(day) + " * *", nil
+	}
+
+	if strings.Contains(rule, "день") || strings.Contains(rule, "ежедневно") {
+		return at + "* * *", nil
+	}
+
+	return "", errors.New(enums.UserErrors[enums.INCORRECT_SCHEDULE])
+}
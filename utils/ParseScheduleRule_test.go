@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"testing"
+)
+
+func TestParseScheduleRule(t *testing.T) {
+	tests := []struct {
+		rule string
+		cron string
+		err  string
+	}{
+		{rule: "ежедневно", cron: "0 9 * * *"},
+		{rule: "каждый день в 7:05", cron: "5 7 * * *"},
+		{rule: "каждый понедельник в 10:30", cron: "30 10 * * 1"},
+		{rule: "каждую среду", cron: "0 9 * * 3"},
+		{rule: "пт 18:00", cron: "0 18 * * 5"},
+		{rule: "по воскресеньям", cron: "0 9 * * 0"},
+		{rule: "каждое 5 число", cron: "0 9 5 * *"},
+		{rule: "ежемесячно 15 числа в 12:00", cron: "0 12 15 * *"},
+		{rule: "0  12 * *   *", cron: "0 12 * * *"},
+		{rule: "*/15 9-18 * * 1-5", cron: "*/15 9-18 * * 1-5"},
+		{rule: "ежедневно в 25:00", err: enums.UserErrors[enums.INCORRECT_SCHEDULE]},
+		{rule: "32 числа каждого месяца", err: enums.UserErrors[enums.INCORRECT_SCHEDULE]},
+		{rule: "когда-нибудь", err: enums.UserErrors[enums.INCORRECT_SCHEDULE]},
+		{rule: "61 * * * *", err: enums.UserErrors[enums.INCORRECT_SCHEDULE]},
+	}
+
+	for _, test := range tests {
+		cron, err := ParseScheduleRule(test.rule)
+
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("ParseScheduleRule(%q) error = %v, want %q", test.rule, err, test.err)
+			}
+
+			continue
+		}
+
+		if err != nil || cron != test.cron {
+			t.Errorf("ParseScheduleRule(%q) = %q, %v, want %q", test.rule, cron, err, test.cron)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var statementDateLayouts = []string{
+	"02.01.2006 15:04:05", "02.01.2006 15:04", "02.01.2006", "02.01.06",
+	"2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02 15:04", "2006-01-02", "02/01/2006",
+}
+
+func ParseStatement(account int, records [][]string, columns models.Columns) ([]models.Operation, error) {
+	var operations []models.Operation
+
+	occurrences := make(map[string]int)
+
+	for _, record := range records {
+		if columns.Date >= len(record) || columns.Amount >= len(record) || columns.Description >= len(record) {
+			continue
+		}
+
+		// the header and summary rows have no date or amount and are skipped
+		date, err := parseStatementDate(record[columns.Date])
+		if err != nil {
+			continue
+		}
+
+		amount, err := parseStatementAmount(record[columns.Amount])
+		if err != nil || amount == 0 {
+			continue
+		}
+
+		description := strings.Join(strings.Fields(record[columns.Description]), " ")
+
+		operation := models.Operation{
+			Account:   account,
+			Operation: enums.BotCommands[enums.INCOME],
+			Amount:    int(math.Round(math.Abs(amount))),
+			Comment:   description,
+			CreatedAt: date.String(),
+		}
+
+		if amount < 0 {
+			operation.Operation = enums.BotCommands[enums.EXPENSE]
+		}
+
+		if operation.Amount == 0 {
+			continue
+		}
+
+		// identical rows of one statement are different operations, so the occurrence is a part of the key
+		key := date.Format("2006-01-02 15:04:05") + "|" + strconv.FormatFloat(amount, 'f', 2, 64) + "|" + description
+		occurrences[key]++
+
+		hash := sha1.Sum([]byte(key + "|" + strconv.Itoa(occurrences[key])))
+		operation.Import = hex.EncodeToString(hash[:])
+
+		operations = append(operations, operation)
+	}
+
+	if len(operations) < 1 {
+		return nil, errors.New(enums.UserErrors[enums.INCORRECT_STATEMENT])
+	}
+
+	return operations, nil
+}
+
+func parseStatementDate(text string) (time.Time, error) {
+	text = strings.TrimSpace(text)
+
+	for _, layout := range statementDateLayouts {
+		if date, err := time.ParseInLocation(layout, text, time.Local); err == nil {
+			return date, nil
+		}
+	}
+
+	return time.Time{}, errors.New(enums.UserErrors[enums.INCORRECT_DATE])
+}
+
+func parseStatementAmount(text string) (float64, error) {
+	text = strings.NewReplacer(" ", "", "\u00a0", "", "\u202f", "", "−", "-", "₽", "", "руб.", "", "RUB", "").Replace(text)
+
+	// "1.234,56" and "1,234.56" both mean 1234.56
+	if strings.Contains(text, ",") && strings.Contains(text, ".") {
+		if strings.LastIndex(text, ",") > strings.LastIndex(text, ".") {
+			text = strings.ReplaceAll(strings.ReplaceAll(text, ".", ""), ",", ".")
+		} else {
+			text = strings.ReplaceAll(text, ",", "")
+		}
+	} else {
+		text = strings.ReplaceAll(text, ",", ".")
+	}
+
+	return strconv.ParseFloat(text, 64)
+}
@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"testing"
+	"time"
+)
+
+func TestParseStatement(t *testing.T) {
+	records := [][]string{
+		{"Дата", "Сумма", "Описание"},
+		{"18.10.2026 12:30", "-1 234,50", "Магазин   у дома"},
+		{"2026-10-17", "50000", "Зарплата"},
+		{"17.10.26", "1.234,56", "Возврат"},
+		{"16.10.2026", "-99.40 ₽", "Кофе"},
+		{"16.10.2026", "-99.40 ₽", "Кофе"},
+		{"16.10.2026", "0,00", "Пустая строка"},
+		{"Итого", "48 000", ""},
+		{"15.10.2026"},
+	}
+
+	operations, err := ParseStatement(1, records, models.Columns{Date: 0, Amount: 1, Description: 2})
+	if err != nil {
+		t.Fatalf("ParseStatement() error = %v", err)
+	}
+
+	income, expense := enums.BotCommands[enums.INCOME], enums.BotCommands[enums.EXPENSE]
+
+	want := []struct {
+		operation string
+		amount    int
+		comment   string
+		date      time.Time
+	}{
+		{operation: expense, amount: 1235, comment: "Магазин у дома", date: time.Date(2026, 10, 18, 12, 30, 0, 0, time.Local)},
+		{operation: income, amount: 50000, comment: "Зарплата", date: time.Date(2026, 10, 17, 0, 0, 0, 0, time.Local)},
+		{operation: income, amount: 1235, comment: "Возврат", date: time.Date(2026, 10, 17, 0, 0, 0, 0, time.Local)},
+		{operation: expense, amount: 99, comment: "Кофе", date: time.Date(2026, 10, 16, 0, 0, 0, 0, time.Local)},
+		{operation: expense, amount: 99, comment: "Кофе", date: time.Date(2026, 10, 16, 0, 0, 0, 0, time.Local)},
+	}
+
+	if len(operations) != len(want) {
+		t.Fatalf("ParseStatement() returned %d operations, want %d", len(operations), len(want))
+	}
+
+	for index, operation := range operations {
+		if operation.Operation != want[index].operation || operation.Amount != want[index].amount ||
+			operation.Comment != want[index].comment || operation.CreatedAt != want[index].date.String() ||
+			operation.Account != 1 || operation.Import == "" {
+			t.Errorf("operation %d = %+v, want %+v", index, operation, want[index])
+		}
+	}
+
+	// identical rows are different operations, so they must not share the import key
+	if operations[3].Import == operations[4].Import {
+		t.Errorf("identical rows share the import key %q", operations[3].Import)
+	}
+
+	if _, err = ParseStatement(1, records[:1], models.Columns{Date: 0, Amount: 1, Description: 2}); err == nil ||
+		err.Error() != enums.UserErrors[enums.INCORRECT_STATEMENT] {
+		t.Errorf("ParseStatement() of a header only error = %v, want %q", err, enums.UserErrors[enums.INCORRECT_STATEMENT])
+	}
+}
+
+func TestParseStatementAmount(t *testing.T) {
+	tests := map[string]float64{
+		"1234.56":      1234.56,
+		"1 234,56":     1234.56,
+		"1.234,56":     1234.56,
+		"1,234.56":     1234.56,
+		"−500":         -500,
+		"-99.40 ₽":     -99.4,
+		"100 руб.":     100,
+		"2\u00a0000":   2000,
+		"+15,5 RUB":    15.5,
+		"0":            0,
+		"1\u202f500,5": 1500.5,
+	}
+
+	for text, want := range tests {
+		if got, err := parseStatementAmount(text); err != nil || got != want {
+			t.Errorf("parseStatementAmount(%q) = %v, %v, want %v", text, got, err, want)
+		}
+	}
+
+	for _, text := range []string{"", "abc", "12-34"} {
+		if _, err := parseStatementAmount(text); err == nil {
+			t.Errorf("parseStatementAmount(%q) error = nil, want an error", text)
+		}
+	}
+}
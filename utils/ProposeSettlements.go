@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"BIEAS_bot/models"
+	"context"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func ProposeSettlements(ctx context.Context, db *models.DataBase, account int, settlements []models.Settlement, author *models.User) (string, error) {
+	batch, err := gonanoid.New()
+	if err != nil {
+		return "", err
+	}
+
+	// the transfers proposed earlier are computed from older balances, so their buttons stop working
+	if _, err = db.Collections["splits"].DeleteMany(ctx, bson.M{
+		"account":  account,
+		"proposed": true,
+	}); err != nil {
+		return "", err
+	}
+
+	// a transfer is recorded as a split paid by the debtor entirely for the creditor, which evens their balances out,
+	// but it counts only when the members confirm the very transfers they were shown
+	for _, settlement := range settlements {
+		split := models.Split{
+			Account:    account,
+			Payer:      settlement.From,
+			Amount:     settlement.Amount,
+			Comment:    "Расчёт",
+			Settlement: true,
+			Batch:      batch,
+			Proposed:   true,
+			Author:     author,
+		}
+
+		if err = CreateSplit(ctx, db, &split, []models.Participant{settlement.To}); err != nil {
+			return "", err
+		}
+	}
+
+	return batch, nil
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+func ReadCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	// statements of russian banks are often saved in windows-1251
+	if !utf8.Valid(data) {
+		decoded, err := charmap.Windows1251.NewDecoder().Bytes(data)
+		if err != nil {
+			return nil, errors.New(enums.UserErrors[enums.INCORRECT_STATEMENT])
+		}
+
+		data = decoded
+	}
+
+	firstLine := string(data)
+	if index := strings.IndexByte(firstLine, '\n'); index >= 0 {
+		firstLine = firstLine[:index]
+	}
+
+	delimiter := ','
+	for _, candidate := range []rune{';', '\t'} {
+		if strings.Count(firstLine, string(candidate)) > strings.Count(firstLine, string(delimiter)) {
+			delimiter = candidate
+		}
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil || len(records) < 1 {
+		return nil, errors.New(enums.UserErrors[enums.INCORRECT_STATEMENT])
+	}
+
+	return records, nil
+}
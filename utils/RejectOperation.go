@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func RejectOperation(ctx context.Context, db *models.DataBase, operation *models.Operation) error {
+	// a rejected request never changed anything, so it is simply forgotten
+	result, err := db.Collections["operations"].DeleteOne(ctx, bson.M{
+		"id":      operation.Id,
+		"pending": true,
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount < 1 {
+		return errors.New(enums.UserErrors[enums.REQUEST_NOT_FOUND])
+	}
+
+	operation.Pending = false
+
+	return nil
+}
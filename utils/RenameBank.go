@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func RenameBank(ctx context.Context, db *models.DataBase, bank *models.Bank, name string) error {
+	existing, err := GetBank(ctx, db, bank.Account, name)
+	if err != nil && err.Error() != enums.UserErrors[enums.BANK_NOT_FOUND] {
+		return err
+	} else if existing != nil {
+		return errors.New(enums.UserErrors[enums.BANK_NAME_IS_EXIST])
+	}
+
+	old := bank.Name
+
+	if err = bank.Update(ctx, db, bson.M{"name": name}); err != nil {
+		return err
+	}
+
+	return UpdateTransferComments(ctx, db, bank, old, name)
+}
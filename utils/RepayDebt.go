@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func RepayDebt(ctx context.Context, db *models.DataBase, debt *models.Debt, bank *models.Bank, amount int, author *models.User) error {
+	if amount > debt.Amount-debt.Repaid {
+		return errors.New(enums.UserErrors[enums.DEBT_OVERPAID])
+	}
+
+	if bank != nil {
+		// a repayment of lent money comes back into the bank, a repayment of borrowed money leaves it
+		operation := models.Operation{
+			Account:   bank.Account,
+			Operation: enums.BotCommands[enums.INCOME],
+			Amount:    amount,
+			Comment:   "Возврат долга: " + debt.Person,
+			Author:    author,
+			Debt:      debt.Id,
+		}
+
+		if debt.Direction == enums.DebtDirections[enums.BORROWED] {
+			operation.Operation = enums.BotCommands[enums.EXPENSE]
+		}
+
+		if err := CreateOperation(ctx, db, bank, &operation); err != nil {
+			return err
+		}
+	}
+
+	return debt.Update(ctx, db, bson.M{
+		"repaid": debt.Repaid + amount,
+		"closed": debt.Repaid+amount >= debt.Amount,
+	})
+}
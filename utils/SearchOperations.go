@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"BIEAS_bot/models"
+	"context"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func SearchOperations(ctx context.Context, db *models.DataBase, account int, text string) ([]models.Operation, error) {
+	var conditions []bson.M
+
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for _, word := range words {
+		// every word has to start a word in the comment, whatever its ending is
+		stem := strings.ReplaceAll(regexp.QuoteMeta(GetStem(word)), "е", "[её]")
+
+		conditions = append(conditions, bson.M{"comment": primitive.Regex{
+			Pattern: `(^|[^а-яёa-z0-9])` + stem,
+			Options: "i",
+		}})
+	}
+
+	var operations []models.Operation
+
+	if len(conditions) < 1 {
+		return operations, nil
+	}
+
+	documents, err := db.GetDocuments(
+		ctx,
+		"operations",
+		bson.M{
+			"account":  account,
+			"reverted": bson.M{"$ne": true},
+			"reverts":  bson.M{"$exists": false},
+			"pending":  bson.M{"$ne": true},
+			"$and":     conditions,
+		},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer documents.Close(ctx)
+
+	if err = documents.All(ctx, &operations); err != nil {
+		return nil, err
+	}
+
+	return operations, nil
+}
@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func SetBankParent(ctx context.Context, db *models.DataBase, bank *models.Bank, parent *models.Bank) error {
+	if parent == nil {
+		return bank.Update(ctx, db, bson.M{"parent": ""})
+	}
+
+	if parent.Id == bank.Id {
+		return errors.New(enums.UserErrors[enums.INCORRECT_PARENT])
+	}
+
+	banks, err := GetBanks(ctx, db, bank.Account)
+	if err != nil {
+		return err
+	}
+
+	for _, descendant := range GetDescendants(banks, bank.Id) {
+		if descendant.Id == parent.Id {
+			return errors.New(enums.UserErrors[enums.INCORRECT_PARENT])
+		}
+	}
+
+	return bank.Update(ctx, db, bson.M{"parent": parent.Id})
+}
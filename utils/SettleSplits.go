@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func SettleSplits(ctx context.Context, db *models.DataBase, account int, batch string, author *models.User) error {
+	// only the transfers proposed together are confirmed, and only once
+	result, err := db.Collections["splits"].UpdateMany(
+		ctx,
+		bson.M{
+			"account":  account,
+			"batch":    batch,
+			"proposed": true,
+		},
+		bson.M{
+			"$unset": bson.M{"proposed": ""},
+			"$set":   bson.M{"author": author},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.ModifiedCount < 1 {
+		return errors.New(enums.UserErrors[enums.SETTLEMENT_NOT_FOUND])
+	}
+
+	return nil
+}
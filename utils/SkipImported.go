@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"BIEAS_bot/models"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func SkipImported(ctx context.Context, db *models.DataBase, account int, operations []models.Operation) ([]models.Operation, int, error) {
+	var hashes []string
+	for _, operation := range operations {
+		hashes = append(hashes, operation.Import)
+	}
+
+	imported, err := db.Collections["operations"].Distinct(ctx, "import", bson.M{
+		"account": account,
+		"import":  bson.M{"$in": hashes},
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	skip := make(map[string]bool)
+	for _, hash := range imported {
+		if value, ok := hash.(string); ok {
+			skip[value] = true
+		}
+	}
+
+	var filtered []models.Operation
+	for _, operation := range operations {
+		if !skip[operation.Import] {
+			filtered = append(filtered, operation)
+		}
+	}
+
+	return filtered, len(operations) - len(filtered), nil
+}
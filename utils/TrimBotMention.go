@@ -0,0 +1,21 @@
+package utils
+
+import "strings"
+
+func TrimBotMention(text string) string {
+	if !strings.HasPrefix(text, "/") {
+		return text
+	}
+
+	command := text
+	if index := strings.IndexAny(text, " \n"); index >= 0 {
+		command = text[:index]
+	}
+
+	// in groups commands are sent as /command@bot
+	if index := strings.Index(command, "@"); index >= 0 {
+		return command[:index] + text[len(command):]
+	}
+
+	return text
+}
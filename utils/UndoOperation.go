@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"errors"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func UndoOperation(ctx context.Context, db *models.DataBase, operation *models.Operation, since time.Time) ([]*models.Bank, error) {
+	if operation.Reverted {
+		return nil, errors.New(enums.UserErrors[enums.ALREADY_REVERTED])
+	}
+
+	if operation.Reverts != "" || ParseCreatedAt(operation.CreatedAt).Before(since) {
+		return nil, errors.New(enums.UserErrors[enums.UNDO_EXPIRED])
+	}
+
+	legs := []models.Operation{*operation}
+
+	if operation.Transfer != "" {
+		transfer, err := GetTransferLegs(ctx, db, operation.Transfer, "")
+		if err != nil {
+			return nil, err
+		}
+
+		legs = transfer
+	}
+
+	// the transfer that covered a shortfall of the operation is undone together with it
+	var covers []models.Operation
+
+	for _, leg := range legs {
+		if leg.Cover == "" {
+			continue
+		}
+
+		cover, err := GetTransferLegs(ctx, db, leg.Cover, "")
+		if err != nil {
+			return nil, err
+		}
+
+		for _, coverLeg := range cover {
+			if !coverLeg.Reverted {
+				covers = append(covers, coverLeg)
+			}
+		}
+	}
+
+	// every leg and bank is checked before anything is written, so the undo is never left half-done
+	var banks []*models.Bank
+	banksById := make(map[string]*models.Bank)
+
+	for _, leg := range append(legs, covers...) {
+		if leg.Reverted {
+			return nil, errors.New(enums.UserErrors[enums.ALREADY_REVERTED])
+		}
+
+		if banksById[leg.Bank] != nil {
+			continue
+		}
+
+		bank, err := GetBankById(ctx, db, leg.Account, leg.Bank)
+		if err != nil {
+			return nil, err
+		}
+
+		banksById[leg.Bank] = bank
+		banks = append(banks, bank)
+	}
+
+	// the operation is marked first, so the same operation cannot be undone twice concurrently
+	result, err := db.Collections["operations"].UpdateOne(
+		ctx,
+		bson.M{
+			"account":  operation.Account,
+			"id":       operation.Id,
+			"reverted": bson.M{"$ne": true},
+		},
+		bson.M{"$set": bson.M{"reverted": true}},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.ModifiedCount < 1 {
+		return nil, errors.New(enums.UserErrors[enums.ALREADY_REVERTED])
+	}
+
+	// compensations of the legs of one transfer make up a transfer of their own
+	transfers := make(map[string]string)
+
+	for _, leg := range append(legs, covers...) {
+		if leg.Id != operation.Id {
+			if err = leg.Update(ctx, db, bson.M{"reverted": true}); err != nil {
+				return nil, err
+			}
+		}
+
+		compensation := models.Operation{
+			Account:   leg.Account,
+			Bank:      leg.Bank,
+			Operation: enums.BotCommands[enums.EXPENSE],
+			Amount:    leg.Amount,
+			Comment:   "Отмена операции: " + leg.Comment,
+			Reverts:   leg.Id,
+		}
+
+		if leg.Transfer != "" {
+			if transfers[leg.Transfer] == "" {
+				if transfers[leg.Transfer], err = gonanoid.New(); err != nil {
+					return nil, err
+				}
+			}
+
+			compensation.Transfer = transfers[leg.Transfer]
+		}
+
+		change := -leg.Amount
+		if leg.Operation == enums.BotCommands[enums.EXPENSE] {
+			compensation.Operation = enums.BotCommands[enums.INCOME]
+			change = leg.Amount
+		}
+
+		if err = compensation.Create(ctx, db); err != nil {
+			return nil, err
+		}
+
+		if err = banksById[leg.Bank].Increase(ctx, db, change); err != nil {
+			return nil, err
+		}
+	}
+
+	return banks, nil
+}
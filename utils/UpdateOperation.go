@@ -0,0 +1,238 @@
+package utils
+
+import (
+	"BIEAS_bot/enums"
+	"BIEAS_bot/models"
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func UpdateOperation(ctx context.Context, db *models.DataBase, operation *models.Operation, edited models.Operation, author *models.User) error {
+	if operation.Reverted || operation.Reverts != "" {
+		return errors.New(enums.UserErrors[enums.OPERATION_IS_REVERTED])
+	}
+
+	if operation.Pending {
+		return errors.New(enums.UserErrors[enums.OPERATION_IS_PENDING])
+	}
+
+	legs, err := GetTransferLegs(ctx, db, operation.Transfer, operation.Id)
+	if err != nil {
+		return err
+	}
+
+	bank, err := GetBankById(ctx, db, operation.Account, operation.Bank)
+	if err != nil {
+		return err
+	}
+
+	target := bank
+	if edited.Bank != operation.Bank {
+		if target, err = GetBankById(ctx, db, operation.Account, edited.Bank); err != nil {
+			return err
+		}
+
+		// a transfer can't go from a bank to itself
+		for _, leg := range legs {
+			if leg.Bank == edited.Bank {
+				return errors.New(enums.UserErrors[enums.SAME_BANK])
+			}
+		}
+	}
+
+	sign := 1
+	if operation.Operation == enums.BotCommands[enums.EXPENSE] {
+		sign = -1
+	}
+
+	banks := map[string]*models.Bank{bank.Id: bank, target.Id: target}
+	changes := map[string]int{}
+
+	changes[bank.Id] -= sign * operation.Amount
+	changes[target.Id] += sign * edited.Amount
+
+	if edited.Amount != operation.Amount {
+		for _, leg := range legs {
+			if banks[leg.Bank] == nil {
+				if banks[leg.Bank], err = GetBankById(ctx, db, leg.Account, leg.Bank); err != nil {
+					return err
+				}
+			}
+
+			change := edited.Amount - operation.Amount
+			if leg.Operation == enums.BotCommands[enums.EXPENSE] {
+				change = -change
+			}
+
+			changes[leg.Bank] += change
+		}
+	}
+
+	// every bank the edit takes money from follows its overdraft policy, as it does for a new expense
+	type cover struct {
+		backup    *models.Bank
+		bank      *models.Bank
+		shortfall int
+	}
+
+	var covers []cover
+
+	for id, change := range changes {
+		if change >= 0 {
+			continue
+		}
+
+		backup, shortfall, err := GetOverdraftCover(ctx, db, banks[id], -change)
+		if err != nil {
+			return err
+		}
+
+		if backup != nil {
+			covers = append(covers, cover{backup: backup, bank: banks[id], shortfall: shortfall})
+		} else if shortfall > 0 && banks[id].Overdraft == enums.OverdraftPolicies[enums.FORBID] {
+			return errors.New(enums.UserErrors[enums.INSUFFICIENT_FUNDS])
+		}
+	}
+
+	for _, cover := range covers {
+		if _, err = CreateTransfer(ctx, db, cover.backup, cover.bank, cover.shortfall, author); err != nil {
+			return err
+		}
+	}
+
+	editedAt := time.Now().String()
+
+	var edits []models.Edit
+	fields := bson.M{}
+
+	if edited.Amount != operation.Amount {
+		fields["amount"] = edited.Amount
+		edits = append(edits, models.Edit{
+			Field: enums.OperationFields[enums.AMOUNT],
+			Old:   strconv.Itoa(operation.Amount),
+			New:   strconv.Itoa(edited.Amount),
+		})
+	}
+
+	if edited.CreatedAt != operation.CreatedAt {
+		fields["created_at"] = edited.CreatedAt
+		edits = append(edits, models.Edit{
+			Field: enums.OperationFields[enums.DATE],
+			Old:   ParseCreatedAt(operation.CreatedAt).Format("02.01.2006 15:04"),
+			New:   ParseCreatedAt(edited.CreatedAt).Format("02.01.2006 15:04"),
+		})
+	}
+
+	// transfer legs share the amount and the date, so only these are copied to the other leg
+	legEdits := edits
+
+	if edited.Comment != operation.Comment {
+		fields["comment"] = edited.Comment
+		edits = append(edits, models.Edit{
+			Field: enums.OperationFields[enums.COMMENT],
+			Old:   operation.Comment,
+			New:   edited.Comment,
+		})
+	}
+
+	if edited.Bank != operation.Bank {
+		fields["bank"] = edited.Bank
+		edits = append(edits, models.Edit{
+			Field: enums.OperationFields[enums.BANK],
+			Old:   bank.Name,
+			New:   target.Name,
+		})
+	}
+
+	if len(edits) < 1 {
+		return nil
+	}
+
+	for index := range edits {
+		edits[index].EditedAt = editedAt
+	}
+
+	if _, err = db.Collections["operations"].UpdateOne(
+		ctx,
+		bson.M{
+			"account": operation.Account,
+			"id":      operation.Id,
+		},
+		bson.M{
+			"$set":  fields,
+			"$push": bson.M{"edits": bson.M{"$each": edits}},
+		},
+	); err != nil {
+		return err
+	}
+
+	if err = bank.Increase(ctx, db, -sign*operation.Amount); err != nil {
+		return err
+	}
+
+	if err = target.Increase(ctx, db, sign*edited.Amount); err != nil {
+		return err
+	}
+
+	for _, leg := range legs {
+		legFields := bson.M{}
+
+		for key, value := range fields {
+			if key == "amount" || key == "created_at" {
+				legFields[key] = value
+			}
+		}
+
+		if edited.Bank != operation.Bank {
+			if leg.Operation == enums.BotCommands[enums.EXPENSE] {
+				legFields["comment"] = "Перевод в копилку " + target.Name
+			} else {
+				legFields["comment"] = "Перевод из копилки " + target.Name
+			}
+		}
+
+		if len(legFields) < 1 {
+			continue
+		}
+
+		for index := range legEdits {
+			legEdits[index].EditedAt = editedAt
+		}
+
+		update := bson.M{"$set": legFields}
+		if len(legEdits) > 0 {
+			update["$push"] = bson.M{"edits": bson.M{"$each": legEdits}}
+		}
+
+		if _, err = db.Collections["operations"].UpdateOne(
+			ctx,
+			bson.M{
+				"account": leg.Account,
+				"id":      leg.Id,
+			},
+			update,
+		); err != nil {
+			return err
+		}
+
+		if edited.Amount != operation.Amount {
+			change := edited.Amount - operation.Amount
+			if leg.Operation == enums.BotCommands[enums.EXPENSE] {
+				change = -change
+			}
+
+			if err = banks[leg.Bank].Increase(ctx, db, change); err != nil {
+				return err
+			}
+		}
+	}
+
+	edited.Edits = append(operation.Edits, edits...)
+	*operation = edited
+
+	return nil
+}
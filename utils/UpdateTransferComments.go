@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"BIEAS_bot/models"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func UpdateTransferComments(ctx context.Context, db *models.DataBase, bank *models.Bank, old string, name string) error {
+	// the other legs of the transfers may belong to the accounts of members, so they are found by the transfer alone
+	transfers, err := db.Collections["operations"].Distinct(ctx, "transfer", bson.M{
+		"bank":     bank.Id,
+		"transfer": bson.M{"$exists": true},
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(transfers) < 1 {
+		return nil
+	}
+
+	comments := map[string]string{
+		"Перевод в копилку " + old:  "Перевод в копилку " + name,
+		"Перевод из копилки " + old: "Перевод из копилки " + name,
+	}
+
+	for comment, renamed := range comments {
+		if _, err = db.Collections["operations"].UpdateMany(
+			ctx,
+			bson.M{
+				"bank":     bson.M{"$ne": bank.Id},
+				"transfer": bson.M{"$in": transfers},
+				"comment":  comment,
+			},
+			bson.M{
+				"$set": bson.M{"comment": renamed},
+			},
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
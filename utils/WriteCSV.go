@@ -0,0 +1,22 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/csv"
+)
+
+func WriteCSV(rows [][]string) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	// the BOM and the semicolon let spreadsheets with russian locale open the file as is
+	buffer.WriteString("\xef\xbb\xbf")
+
+	writer := csv.NewWriter(&buffer)
+	writer.Comma = ';'
+
+	if err := writer.WriteAll(rows); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"BIEAS_bot/models"
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"strconv"
+	"strings"
+)
+
+func WriteXLSX(sheets []models.Sheet) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	archive := zip.NewWriter(&buffer)
+
+	var contentTypes, workbook, relationships string
+
+	for index, sheet := range sheets {
+		number := strconv.Itoa(index + 1)
+
+		contentTypes += `<Override PartName="/xl/worksheets/sheet` + number + `.xml" ` +
+			`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`
+		workbook += `<sheet name="` + escapeXML(sheet.Name) + `" sheetId="` + number + `" r:id="rId` + number + `"/>`
+		relationships += `<Relationship Id="rId` + number + `" ` +
+			`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" ` +
+			`Target="worksheets/sheet` + number + `.xml"/>`
+	}
+
+	files := []struct {
+		Name    string
+		Content string
+	}{
+		{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ` +
+			`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			contentTypes + `</Types>`},
+		{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" ` +
+			`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" ` +
+			`Target="xl/workbook.xml"/></Relationships>`},
+		{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+			`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets>` + workbook + `</sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			relationships + `</Relationships>`},
+	}
+
+	for index, sheet := range sheets {
+		files = append(files, struct {
+			Name    string
+			Content string
+		}{"xl/worksheets/sheet" + strconv.Itoa(index+1) + ".xml", getSheetXML(sheet)})
+	}
+
+	for _, file := range files {
+		writer, err := archive.Create(file.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err = writer.Write([]byte(file.Content)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func getSheetXML(sheet models.Sheet) string {
+	var builder strings.Builder
+
+	builder.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	for rowIndex, row := range sheet.Rows {
+		builder.WriteString(`<row r="` + strconv.Itoa(rowIndex+1) + `">`)
+
+		for columnIndex, value := range row {
+			reference := getColumnName(columnIndex) + strconv.Itoa(rowIndex+1)
+
+			// amounts are written as numbers, so that they can be summed in the spreadsheet
+			if _, err := strconv.Atoi(value); err == nil && rowIndex > 0 {
+				builder.WriteString(`<c r="` + reference + `"><v>` + value + `</v></c>`)
+			} else {
+				builder.WriteString(`<c r="` + reference + `" t="inlineStr"><is><t>` + escapeXML(value) + `</t></is></c>`)
+			}
+		}
+
+		builder.WriteString(`</row>`)
+	}
+
+	builder.WriteString(`</sheetData></worksheet>`)
+
+	return builder.String()
+}
+
+func getColumnName(index int) string {
+	name := ""
+	for index++; index > 0; index = (index - 1) / 26 {
+		name = string(rune('A'+(index-1)%26)) + name
+	}
+
+	return name
+}
+
+func escapeXML(text string) string {
+	var buffer bytes.Buffer
+	xml.EscapeText(&buffer, []byte(text))
+
+	return buffer.String()
+}